@@ -43,28 +43,30 @@ func main() {
 	fmt.Printf("📢 Events:       %d\n", stats["events"])
 	fmt.Println()
 
-	// Get camera status
-	camera, err := storageService.GetCameraStatus()
+	// Get camera statuses
+	cameras, err := storageService.ListAllCameras()
 	if err != nil {
 		log.Printf("⚠️  Warning: Could not get camera status: %v", err)
 	} else {
 		fmt.Println("📹 CAMERA STATUS")
 		fmt.Println("=================")
-		fmt.Printf("Name:        %s\n", camera.Name)
-		fmt.Printf("Status:      %s", camera.Status)
-		if camera.IsOnline() {
-			fmt.Printf(" 🟢 (Online)")
-		} else {
-			fmt.Printf(" 🔴 (Offline)")
-		}
-		fmt.Println()
-		if camera.LastPing != nil {
-			fmt.Printf("Last Ping:   %s\n", camera.LastPing.Format("2006-01-02 15:04:05"))
-		} else {
-			fmt.Printf("Last Ping:   Never\n")
+		for _, camera := range cameras {
+			fmt.Printf("Name:        %s (id %d)\n", camera.Name, camera.ID)
+			fmt.Printf("Status:      %s", camera.Status)
+			if camera.IsOnline() {
+				fmt.Printf(" 🟢 (Online)")
+			} else {
+				fmt.Printf(" 🔴 (Offline)")
+			}
+			fmt.Println()
+			if camera.LastPing != nil {
+				fmt.Printf("Last Ping:   %s\n", camera.LastPing.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("Last Ping:   Never\n")
+			}
+			fmt.Printf("Created:     %s\n", camera.CreatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Println()
 		}
-		fmt.Printf("Created:     %s\n", camera.CreatedAt.Format("2006-01-02 15:04:05"))
-		fmt.Println()
 	}
 
 	// Get recent events
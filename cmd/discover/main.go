@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"camera-detection-project/internal/camera"
+	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/storage"
+)
+
+func main() {
+	var (
+		host       = flag.String("host", "", "Host or IP to probe (required)")
+		routesPath = flag.String("routes", "", "Path to a newline-separated routes file (defaults to the built-in list)")
+		credsPath  = flag.String("creds", "", "Path to a newline-separated user:pass file (defaults to the built-in list)")
+		timeout    = flag.Duration("timeout", 3*time.Second, "Per-attempt RTSP DESCRIBE timeout")
+		save       = flag.Bool("save", false, "Persist each hit as a candidate camera via storage.Service")
+	)
+	flag.Parse()
+
+	if *host == "" {
+		log.Fatal("Usage: discover -host <ip-or-hostname> [-routes file] [-creds file] [-save]")
+	}
+
+	opts := camera.DefaultDiscoverOptions()
+	opts.Timeout = *timeout
+
+	if *routesPath != "" {
+		data, err := os.ReadFile(*routesPath)
+		if err != nil {
+			log.Fatalf("Failed to read routes file: %v", err)
+		}
+		opts.Routes = camera.ParseRoutes(string(data))
+	}
+	if *credsPath != "" {
+		data, err := os.ReadFile(*credsPath)
+		if err != nil {
+			log.Fatalf("Failed to read creds file: %v", err)
+		}
+		opts.Creds = camera.ParseCreds(string(data))
+	}
+
+	log.Printf("🔎 Probing %s on ports %v (%d routes x %d credentials)...", *host, opts.Ports, len(opts.Routes), len(opts.Creds))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	results, err := camera.Discover(ctx, *host, opts)
+	if err != nil {
+		log.Fatalf("Discovery failed: %v", err)
+	}
+
+	if len(results) == 0 {
+		log.Println("No RTSP streams found")
+		return
+	}
+
+	var storageService *storage.Service
+	if *save {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+		storageService, err = storage.NewService(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize storage service: %v", err)
+		}
+		defer storageService.Close()
+	}
+
+	for _, r := range results {
+		log.Printf("✅ %s [%s, %s] codecs=%v", r.URL(), r.Vendor, r.Transport, r.Codecs)
+
+		if storageService == nil {
+			continue
+		}
+
+		cam := &storage.Camera{
+			Name:     fmt.Sprintf("Discovered %s:%d%s", r.Host, r.Port, r.Route),
+			RTSPURL:  fmt.Sprintf("rtsp://%s:%d%s", r.Host, r.Port, r.Route),
+			Username: r.Username,
+			Password: r.Password,
+			Status:   storage.CameraStatusDiscovered,
+		}
+		if err := storageService.CreateCamera(cam); err != nil {
+			log.Printf("⚠️  Could not save discovered camera %s: %v", r.URL(), err)
+		}
+	}
+}
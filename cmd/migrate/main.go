@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"camera-detection-project/internal/config"
 	"camera-detection-project/internal/storage"
@@ -17,6 +18,7 @@ func main() {
 
 	// Create database configuration
 	dbConfig := &storage.DatabaseConfig{
+		URL:          cfg.DatabaseURL,
 		Host:         cfg.DatabaseHost,
 		Port:         cfg.DatabasePort,
 		User:         cfg.DatabaseUser,
@@ -36,12 +38,12 @@ func main() {
 
 	log.Println("✅ Connected to database successfully")
 
-	// Create tables
-	if err := db.CreateTables(); err != nil {
-		log.Fatalf("❌ Failed to create tables: %v", err)
+	// Apply pending schema migrations
+	if err := db.Migrate(context.Background(), "up"); err != nil {
+		log.Fatalf("❌ Failed to apply migrations: %v", err)
 	}
 
-	log.Println("✅ Database tables created successfully")
+	log.Println("✅ Database migrations applied successfully")
 
 	// Show statistics
 	stats, err := db.GetDatabaseStats()
@@ -1,21 +1,47 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"camera-detection-project/internal/api"
+	"camera-detection-project/internal/broadcast"
 	"camera-detection-project/internal/camera"
 	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/eventbus"
+	"camera-detection-project/internal/metrics"
 	"camera-detection-project/internal/storage"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+
+	publishTarget := flag.String("publish", "", "RTMP target URL to start re-publishing the first configured camera to at startup (e.g. rtmp://host/live/stream)")
+	frameRate := flag.Int("frame-rate", 0, "override FRAME_RATE from config.yaml/.env/environment (0 = don't override)")
+	confidenceThreshold := flag.Float64("confidence-threshold", -1, "override the detection confidence threshold (negative = don't override)")
+	flag.Parse()
+
 	log.Println("Starting camera detection service...")
 
-	// Load configuration
-	cfg, err := config.Load()
+	// Load configuration, layering config.yaml, .env, the environment, and
+	// any of the flags above, in increasing order of precedence.
+	var cliOverrides config.CLIOverrides
+	if *frameRate > 0 {
+		cliOverrides.FrameRate = frameRate
+	}
+	if *confidenceThreshold >= 0 {
+		cliOverrides.ConfidenceThreshold = confidenceThreshold
+	}
+
+	cfg, err := config.Load(config.WithCLI(cliOverrides))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -27,7 +53,29 @@ func main() {
 	}
 	defer storageService.Close()
 
-	// Initialize database tables if needed
+	// Wire an event broker so the SSE stream gets every event as it's created
+	broker := eventbus.NewBroker()
+	storageService.SetEventBroker(broker)
+
+	// Start the hardware/pipeline metrics sampler
+	sampler := metrics.NewSampler(storageService, cfg.OutputDir, cfg.MetricsSampleInterval, cfg.DiskFreeThresholdBytes)
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	defer stopMetrics()
+	go sampler.Run(metricsCtx)
+
+	// Wire a broadcast registry so /webrtc/<camera> can be served as soon as
+	// the API server starts, even before the camera's Manager is added below
+	broadcastRegistry := broadcast.NewRegistry()
+
+	apiServer := api.NewServer(storageService, broker, sampler, broadcastRegistry)
+	go func() {
+		addr := fmt.Sprintf(":%d", cfg.APIPort)
+		if err := apiServer.Start(addr); err != nil {
+			log.Printf("Warning: API server stopped: %v", err)
+		}
+	}()
+
+	// Apply any pending schema migrations
 	if err := storageService.InitializeDatabase(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
@@ -42,21 +90,60 @@ func main() {
 		log.Printf("Warning: Could not create startup event: %v", err)
 	}
 
-	// Create camera client with storage integration
-	client, err := camera.NewFFmpegClientWithStorage(cfg, storageService)
+	// Create the camera manager, which starts one capture client per row in
+	// the cameras table and keeps them in sync with it afterward
+	cameraManager, err := camera.NewManager(cfg, storageService)
 	if err != nil {
-		log.Fatalf("Failed to create camera client: %v", err)
+		log.Fatalf("Failed to create camera manager: %v", err)
 	}
-	defer client.Close()
-
-	// Update camera status to active
-	if err := storageService.UpdateCameraStatus(storage.CameraStatusActive); err != nil {
-		log.Printf("Warning: Could not update camera status: %v", err)
+	defer cameraManager.Close()
+
+	// Watch config.yaml and push any change straight to the camera manager,
+	// so e.g. a tuned confidence threshold takes effect without a restart.
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if err := cfg.Watch(watchCtx, cameraManager.Reconfigure); err != nil {
+		log.Printf("Warning: could not watch configuration file for changes: %v", err)
 	}
 
-	// Start video processing
-	if err := client.Start(); err != nil {
-		log.Fatalf("Failed to start camera client: %v", err)
+	// Wire a broadcast manager for every camera (RTMP/HLS/WebRTC egress), so
+	// /webrtc/<id> can serve a live preview of any managed camera, not just
+	// the default one.
+	broadcastManagers := make(map[int]*broadcast.Manager)
+	cams, err := storageService.ListAllCameras()
+	if err != nil {
+		log.Printf("Warning: could not list cameras for broadcast managers: %v", err)
+	} else {
+		for _, cam := range cams {
+			client, ok := cameraManager.Client(cam.ID)
+			if !ok {
+				log.Printf("Warning: camera %d has no running capture client for broadcast manager", cam.ID)
+				continue
+			}
+			bm := broadcast.NewManager(cam.ID, client, storageService, cfg.OutputDir)
+			broadcastRegistry.Add(cam.ID, bm)
+			broadcastManagers[cam.ID] = bm
+		}
+	}
+	defer func() {
+		for _, bm := range broadcastManagers {
+			bm.Close()
+		}
+	}()
+
+	if *publishTarget != "" {
+		if len(cams) == 0 {
+			log.Printf("Warning: no cameras configured, nothing to RTMP publish")
+		} else if bm, ok := broadcastManagers[cams[0].ID]; ok {
+			// -publish is a single target, so it always goes to the first
+			// configured camera; multi-camera RTMP publishing goes through
+			// the broadcast API per camera instead.
+			if err := bm.Start(*publishTarget); err != nil {
+				log.Printf("Warning: could not start RTMP publish to %s: %v", *publishTarget, err)
+			}
+		} else {
+			log.Printf("Warning: camera %d has no broadcast manager for RTMP publish", cams[0].ID)
+		}
 	}
 
 	log.Println("Camera detection service started successfully")
@@ -68,11 +155,6 @@ func main() {
 
 	log.Println("Shutting down...")
 
-	// Update camera status to inactive
-	if err := storageService.UpdateCameraStatus(storage.CameraStatusInactive); err != nil {
-		log.Printf("Warning: Could not update camera status: %v", err)
-	}
-
 	// Create system shutdown event
 	if err := storageService.CreateSystemEvent(
 		storage.EventTypeSystemStop,
@@ -83,8 +165,46 @@ func main() {
 		log.Printf("Warning: Could not create shutdown event: %v", err)
 	}
 
-	// Stop camera client
-	client.Stop()
-
 	log.Println("Shutdown completed")
-}
\ No newline at end of file
+}
+
+// runMigrateCommand implements the "migrate" subcommand: up, down, status,
+// or redo against the configured database, without starting the server.
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		log.Fatal("Usage: server migrate <up|down|status|redo>")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	storageService, err := storage.NewService(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage service: %v", err)
+	}
+	defer storageService.Close()
+
+	switch args[0] {
+	case "up", "down", "redo":
+		if err := storageService.Migrate(args[0]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Printf("Migrate %s completed", args[0])
+	case "status":
+		statuses, err := storageService.MigrationStatus()
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = fmt.Sprintf("applied at %s", s.AppliedAt.Format("2006-01-02 15:04:05"))
+			}
+			fmt.Printf("%04d_%s: %s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatalf("Unknown migrate subcommand %q (expected up, down, status, or redo)", args[0])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"log"
 	"os"
+	"time"
 
 	"camera-detection-project/internal/camera"
 	"camera-detection-project/internal/config"
@@ -14,6 +15,8 @@ func main() {
 		testConnection = flag.Bool("connection", false, "Test RTSP connection only")
 		captureFrame   = flag.Bool("frame", false, "Capture a single frame")
 		recordSegment  = flag.Int("record", 0, "Record video segment (seconds)")
+		preRoll        = flag.Int("preroll", 0, "Pre-roll seconds for -postroll's event clip")
+		postRoll       = flag.Int("postroll", 0, "Record a pre/post-roll clip around a simulated event (seconds after the trigger)")
 		rtspURL        = flag.String("url", "", "RTSP URL (overrides config)")
 	)
 	flag.Parse()
@@ -41,6 +44,8 @@ func main() {
 		captureFrameOnly(cfg)
 	case *recordSegment > 0:
 		recordVideoSegment(cfg, *recordSegment)
+	case *postRoll > 0:
+		recordEventClip(cfg, *preRoll, *postRoll)
 	default:
 		runFullTest(cfg)
 	}
@@ -48,58 +53,59 @@ func main() {
 
 func testConnectionOnly(cfg *config.Config) {
 	log.Println("🔌 Testing RTSP connection...")
-	
+
 	if err := camera.TestRTSPConnectionWithConfig(cfg); err != nil {
 		log.Fatalf("❌ Connection test failed: %v", err)
 	}
-	
+
 	log.Println("✅ Connection test successful!")
 }
 
 func captureFrameOnly(cfg *config.Config) {
 	log.Println("📸 Capturing single frame...")
-	
+
 	outputPath := "output/test_frame.jpg"
 	if err := camera.ExtractFrameWithConfig(cfg, outputPath); err != nil {
 		log.Fatalf("❌ Frame capture failed: %v", err)
 	}
-	
+
 	log.Printf("✅ Frame captured: %s", outputPath)
 }
 
 func recordVideoSegment(cfg *config.Config, duration int) {
 	log.Printf("🎥 Recording %d second video segment...", duration)
-	
+
 	outputPath := "output/test_recording.mp4"
-	rtspURL := buildRTSPURL(cfg.RTSPURL, cfg.Username, cfg.Password)
-	
-	if err := camera.RecordVideoSegment(rtspURL, outputPath, duration); err != nil {
+
+	if err := camera.RecordSegment(cfg, outputPath, time.Duration(duration)*time.Second); err != nil {
 		log.Fatalf("❌ Video recording failed: %v", err)
 	}
-	
+
 	log.Printf("✅ Video recorded: %s", outputPath)
 }
 
+func recordEventClip(cfg *config.Config, preRollSec, postRollSec int) {
+	log.Printf("🎯 Recording %ds pre-roll + %ds post-roll event clip...", preRollSec, postRollSec)
+
+	outputPath := "output/test_event_clip.mp4"
+	preRoll := time.Duration(preRollSec) * time.Second
+	postRoll := time.Duration(postRollSec) * time.Second
+
+	if err := camera.RecordAroundEvent(cfg, outputPath, preRoll, postRoll); err != nil {
+		log.Fatalf("❌ Event clip recording failed: %v", err)
+	}
+
+	log.Printf("✅ Event clip recorded: %s", outputPath)
+}
+
 func runFullTest(cfg *config.Config) {
 	log.Println("🚀 Running full camera test...")
-	
+
 	if err := camera.QuickCameraTest(cfg); err != nil {
 		log.Fatalf("❌ Camera test failed: %v", err)
 	}
-	
-	log.Println("🎉 All tests completed successfully!")
-}
 
-// buildRTSPURL constructs RTSP URL with credentials
-func buildRTSPURL(baseURL, username, password string) string {
-	if username == "" || password == "" {
-		return baseURL
-	}
-	
-	if baseURL[:7] == "rtsp://" {
-		return "rtsp://" + username + ":" + password + "@" + baseURL[7:]
-	}
-	return baseURL
+	log.Println("🎉 All tests completed successfully!")
 }
 
 func init() {
@@ -107,4 +113,4 @@ func init() {
 	if err := os.MkdirAll("output", 0755); err != nil {
 		log.Printf("Warning: could not create output directory: %v", err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultListLimit bounds how many recordings/frames a single request
+// returns when the caller doesn't specify a limit.
+const defaultListLimit = 100
+
+// handleListCameras serves GET /api/cameras, returning every camera the
+// authenticated user has any ACL role on.
+func (s *Server) handleListCameras(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r)
+
+	cameras, err := s.storage.GetCamerasForUser(userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list cameras")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cameras)
+}
+
+// handleListRecordings serves GET /api/cameras/recordings?camera_id=, scoped
+// to callers with some ACL role on that camera.
+func (s *Server) handleListRecordings(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r)
+
+	cameraID, err := strconv.Atoi(r.URL.Query().Get("camera_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "camera_id is required")
+		return
+	}
+
+	limit := defaultListLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	recordings, err := s.storage.GetRecordingsByCameraForUser(userID, cameraID, limit)
+	if err != nil {
+		writeError(w, http.StatusForbidden, "no access to this camera")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recordings)
+}
+
+// handleListFrames serves GET /api/cameras/frames?camera_id=, scoped to
+// callers with some ACL role on that camera. start/end default to the
+// last 24 hours if omitted.
+func (s *Server) handleListFrames(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r)
+
+	cameraID, err := strconv.Atoi(r.URL.Query().Get("camera_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "camera_id is required")
+		return
+	}
+
+	end := time.Now()
+	if v := r.URL.Query().Get("end"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			end = t
+		}
+	}
+	start := end.Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("start"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			start = t
+		}
+	}
+
+	limit := defaultListLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	frames, err := s.storage.GetFramesByTimeRangeForUser(userID, cameraID, start, end, limit)
+	if err != nil {
+		writeError(w, http.StatusForbidden, "no access to this camera")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, frames)
+}
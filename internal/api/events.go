@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"camera-detection-project/internal/storage"
+)
+
+// replayLimit bounds how many missed events are replayed to a reconnecting
+// client before it switches over to live push.
+const replayLimit = 500
+
+// heartbeatInterval keeps intermediary proxies from closing idle SSE
+// connections.
+const heartbeatInterval = 15 * time.Second
+
+// severityRank orders severities so a "minimum severity" filter can be
+// expressed as a simple integer comparison.
+var severityRank = map[string]int{
+	storage.SeverityLow:      0,
+	storage.SeverityMedium:   1,
+	storage.SeverityHigh:     2,
+	storage.SeverityCritical: 3,
+}
+
+// eventFilter narrows a stream to a camera and/or a minimum severity, and
+// restricts it to the cameras the requesting user has ACL access to.
+type eventFilter struct {
+	cameraID       int // 0 means "any camera"
+	minSeverity    int
+	allowedCameras map[int]bool // nil means no ACL restriction
+}
+
+func parseEventFilter(r *http.Request, allowedCameras map[int]bool) eventFilter {
+	f := eventFilter{allowedCameras: allowedCameras}
+
+	if v := r.URL.Query().Get("camera_id"); v != "" {
+		if id, err := strconv.Atoi(v); err == nil {
+			f.cameraID = id
+		}
+	}
+
+	if v := r.URL.Query().Get("severity"); v != "" {
+		if rank, ok := severityRank[strings.ToLower(v)]; ok {
+			f.minSeverity = rank
+		}
+	}
+
+	return f
+}
+
+// matches reports whether an event passes the filter. Events with no
+// camera (system-wide events) always pass the ACL check; per-camera events
+// require the camera to be in allowedCameras.
+func (f eventFilter) matches(cameraID *int, severity string) bool {
+	if f.cameraID != 0 && (cameraID == nil || *cameraID != f.cameraID) {
+		return false
+	}
+	if f.allowedCameras != nil && cameraID != nil && !f.allowedCameras[*cameraID] {
+		return false
+	}
+	if severityRank[severity] < f.minSeverity {
+		return false
+	}
+	return true
+}
+
+// handleEventStream serves GET /api/events/stream, pushing each newly
+// created event to the client over Server-Sent Events. A reconnecting client
+// sends a Last-Event-ID header so missed events are replayed before the
+// handler switches over to live push.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	userID, _ := userIDFromContext(r)
+	cameras, err := s.storage.GetCamerasForUser(userID)
+	if err != nil {
+		http.Error(w, "failed to resolve camera access", http.StatusInternalServerError)
+		return
+	}
+	allowedCameras := make(map[int]bool, len(cameras))
+	for _, camera := range cameras {
+		allowedCameras[camera.ID] = true
+	}
+
+	filter := parseEventFilter(r, allowedCameras)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if lastID, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+		s.replayMissedEvents(w, flusher, lastID, filter)
+	}
+
+	eventCh, unsubscribe := s.broker.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ":heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case evt, ok := <-eventCh:
+			if !ok {
+				return
+			}
+			var meta struct {
+				CameraID *int   `json:"camera_id"`
+				Severity string `json:"severity"`
+			}
+			if err := json.Unmarshal(evt.Data, &meta); err != nil || !filter.matches(meta.CameraID, meta.Severity) {
+				continue
+			}
+			writeSSE(w, evt.ID, evt.Type, evt.Data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) replayMissedEvents(w http.ResponseWriter, flusher http.Flusher, lastID int, filter eventFilter) {
+	events, err := s.storage.GetEventsAfter(lastID, replayLimit)
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		if !filter.matches(event.CameraID, event.Severity) {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		writeSSE(w, event.ID, event.EventType, data)
+	}
+	flusher.Flush()
+}
+
+// writeSSE writes a single Server-Sent Event using the wire format: an id
+// line, an event line, one or more data lines (payloads are split on
+// newlines, each becoming its own "data:" line), then a blank line.
+func writeSSE(w http.ResponseWriter, id int, eventType string, data []byte) {
+	fmt.Fprintf(w, "id: %d\n", id)
+	fmt.Fprintf(w, "event: %s\n", eventType)
+	for _, line := range strings.Split(string(data), "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// sessionCookieName is the HttpOnly cookie that carries a user's session
+// token, set on register/login and read by authMiddleware.
+const sessionCookieName = "session_token"
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// authMiddleware rejects requests without a valid session cookie, and
+// attaches the authenticated user's ID to the request context otherwise.
+func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookieName)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		userID, err := s.storage.VerifyToken(cookie.Value)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// userIDFromContext returns the authenticated user's ID, as attached by
+// authMiddleware.
+func userIDFromContext(r *http.Request) (int, bool) {
+	userID, ok := r.Context().Value(userIDContextKey).(int)
+	return userID, ok
+}
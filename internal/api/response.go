@@ -0,0 +1,27 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Response is the shared JSON envelope returned by every handler in this
+// package, so clients can always check the same two fields regardless of
+// which endpoint they called.
+type Response struct {
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Response carrying err as a JSON error with the given
+// status code.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, Response{Error: message})
+}
@@ -0,0 +1,60 @@
+// Package api exposes the camera detection system's HTTP endpoints, such as
+// the real-time event stream.
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"camera-detection-project/internal/broadcast"
+	"camera-detection-project/internal/eventbus"
+	"camera-detection-project/internal/metrics"
+	"camera-detection-project/internal/storage"
+)
+
+// Server holds the dependencies shared by the HTTP handlers.
+type Server struct {
+	mux       *http.ServeMux
+	storage   *storage.Service
+	broker    *eventbus.Broker
+	sampler   *metrics.Sampler
+	broadcast *broadcast.Registry
+}
+
+// NewServer creates an HTTP server backed by the given storage service,
+// event broker, metrics sampler, and broadcast registry.
+func NewServer(storageService *storage.Service, broker *eventbus.Broker, sampler *metrics.Sampler, broadcastRegistry *broadcast.Registry) *Server {
+	s := &Server{
+		mux:       http.NewServeMux(),
+		storage:   storageService,
+		broker:    broker,
+		sampler:   sampler,
+		broadcast: broadcastRegistry,
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("/api/user/register", s.handleRegister)
+	s.mux.HandleFunc("/api/user/login", s.handleLogin)
+	s.mux.HandleFunc("/api/events/stream", s.authMiddleware(s.handleEventStream))
+	s.mux.HandleFunc("/api/stats/hardware", s.authMiddleware(s.handleHardwareStats))
+	s.mux.HandleFunc("/api/cameras", s.authMiddleware(s.handleListCameras))
+	s.mux.HandleFunc("/api/cameras/recordings", s.authMiddleware(s.handleListRecordings))
+	s.mux.HandleFunc("/api/cameras/frames", s.authMiddleware(s.handleListFrames))
+	s.mux.HandleFunc("/metrics", s.handlePrometheusMetrics)
+	s.mux.HandleFunc("/webrtc/", s.authMiddleware(s.handleWebRTCOffer))
+}
+
+// Start begins serving HTTP requests on addr. It blocks until the server
+// stops or fails.
+func (s *Server) Start(addr string) error {
+	log.Printf("API server listening on %s", addr)
+	return http.ListenAndServe(addr, s.mux)
+}
+
+// Handler returns the underlying http.Handler, e.g. for use in tests.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// handleHardwareStats serves GET /api/stats/hardware, returning the
+// sampler's current ring buffer as JSON, oldest sample first.
+func (s *Server) handleHardwareStats(w http.ResponseWriter, r *http.Request) {
+	if s.sampler == nil {
+		writeError(w, http.StatusServiceUnavailable, "metrics sampler not configured")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.sampler.Ring().Snapshot())
+}
+
+// handlePrometheusMetrics serves GET /metrics in Prometheus text exposition
+// format, using the sampler's most recent sample.
+func (s *Server) handlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	if s.sampler == nil {
+		return
+	}
+
+	sample, ok := s.sampler.Ring().Latest()
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP camera_detection_cpu_percent Host CPU utilization percentage.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_cpu_percent gauge\n")
+	fmt.Fprintf(w, "camera_detection_cpu_percent %f\n", sample.CPUPercent)
+
+	fmt.Fprintf(w, "# HELP camera_detection_memory_percent Host memory utilization percentage.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_memory_percent gauge\n")
+	fmt.Fprintf(w, "camera_detection_memory_percent %f\n", sample.MemoryPercent)
+
+	fmt.Fprintf(w, "# HELP camera_detection_disk_free_bytes Free disk space on the recordings volume.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_disk_free_bytes gauge\n")
+	fmt.Fprintf(w, "camera_detection_disk_free_bytes %d\n", sample.DiskFreeBytes)
+
+	fmt.Fprintf(w, "# HELP camera_detection_disk_used_bytes Used disk space on the recordings volume.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_disk_used_bytes gauge\n")
+	fmt.Fprintf(w, "camera_detection_disk_used_bytes %d\n", sample.DiskUsedBytes)
+
+	fmt.Fprintf(w, "# HELP camera_detection_frame_backlog Unprocessed frames awaiting detection.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_frame_backlog gauge\n")
+	fmt.Fprintf(w, "camera_detection_frame_backlog %d\n", sample.BacklogSize)
+
+	fmt.Fprintf(w, "# HELP camera_detection_dropped_frames_total Frames discarded instead of persisted.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_dropped_frames_total counter\n")
+	fmt.Fprintf(w, "camera_detection_dropped_frames_total %d\n", sample.DroppedFrames)
+
+	fmt.Fprintf(w, "# HELP camera_detection_camera_fps Per-camera capture frame rate.\n")
+	fmt.Fprintf(w, "# TYPE camera_detection_camera_fps gauge\n")
+	for cameraID, fps := range sample.CameraFPS {
+		fmt.Fprintf(w, "camera_detection_camera_fps{camera_id=\"%d\"} %f\n", cameraID, fps)
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"camera-detection-project/internal/auth"
+)
+
+// credentials is the request body shared by register and login.
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// handleRegister serves POST /api/user/register, creating a new account and
+// logging it in immediately by setting the same session cookie as login.
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil || creds.Username == "" || creds.Password == "" {
+		writeError(w, http.StatusBadRequest, "username and password are required")
+		return
+	}
+
+	user, err := s.storage.RegisterUser(creds.Username, creds.Password)
+	if err != nil {
+		writeError(w, http.StatusConflict, "failed to register user")
+		return
+	}
+
+	_, token, err := s.storage.AuthenticateUser(creds.Username, creds.Password)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to issue session")
+		return
+	}
+
+	setSessionCookie(w, token)
+	log.Printf("Registered user: %s (ID: %d)", user.Username, user.ID)
+	writeJSON(w, http.StatusCreated, Response{Message: "user registered"})
+}
+
+// handleLogin serves POST /api/user/login, setting an HttpOnly session
+// cookie on success.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	_, token, err := s.storage.AuthenticateUser(creds.Username, creds.Password)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+
+	setSessionCookie(w, token)
+	writeJSON(w, http.StatusOK, Response{Message: "logged in"})
+}
+
+func setSessionCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(auth.TokenTTL.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"camera-detection-project/internal/broadcast"
+)
+
+// handleWebRTCOffer gates broadcast.Registry.WHEPHandler behind the same
+// per-camera ACL every other camera-scoped route enforces: the requesting
+// user must hold some role on the camera named in the /webrtc/<id> path, or
+// they get a 403 before the offer ever reaches the broadcast package.
+func (s *Server) handleWebRTCOffer(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r)
+
+	cameraID, err := broadcast.CameraIDFromPath(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := s.storage.GetUserRole(userID, cameraID); err != nil {
+		writeError(w, http.StatusForbidden, "no access to this camera")
+		return
+	}
+
+	s.broadcast.WHEPHandler(w, r)
+}
@@ -0,0 +1,102 @@
+// Package auth provides password hashing and session token issuing for the
+// users/auth subsystem: bcrypt for stored passwords, and a small
+// HMAC-signed token (a minimal stand-in for a JWT, carrying a single user
+// ID claim) for the session cookie set by login/register.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TokenTTL is how long an issued session token remains valid.
+const TokenTTL = 7 * 24 * time.Hour
+
+// ErrInvalidToken indicates a token that is malformed, expired, or whose
+// signature doesn't match.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// HashPassword bcrypt-hashes a plaintext password for storage.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the bcrypt hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Issuer signs and verifies session tokens asserting a user ID.
+type Issuer struct {
+	secret []byte
+}
+
+// NewIssuer creates an Issuer that signs tokens with secret.
+func NewIssuer(secret string) *Issuer {
+	return &Issuer{secret: []byte(secret)}
+}
+
+// Issue returns a signed token for userID, valid for TokenTTL.
+func (iss *Issuer) Issue(userID int) string {
+	payload := fmt.Sprintf("%d.%d", userID, time.Now().Add(TokenTTL).Unix())
+	sig := iss.sign(payload)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// Verify checks a token's signature and expiry, returning the user ID it
+// asserts.
+func (iss *Issuer) Verify(token string) (int, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return 0, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	if !hmac.Equal(sig, iss.sign(string(payload))) {
+		return 0, ErrInvalidToken
+	}
+
+	fields := strings.SplitN(string(payload), ".", 2)
+	if len(fields) != 2 {
+		return 0, ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	expiry, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	if time.Now().Unix() > expiry {
+		return 0, ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+func (iss *Issuer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, iss.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
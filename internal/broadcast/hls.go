@@ -0,0 +1,125 @@
+package broadcast
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"camera-detection-project/internal/camera"
+	"camera-detection-project/internal/packets"
+)
+
+// hlsSegmentDuration is how much of the source stream each HLS segment
+// file covers before the playlist rolls over to the next one.
+const hlsSegmentDuration = 6 * time.Second
+
+// hlsWindowSize is how many completed segments stay referenced in the
+// live playlist; older segment files are deleted as they age out.
+const hlsWindowSize = 5
+
+// startHLSWriter subscribes to client's packets and continuously writes
+// numbered segment files plus a live playlist.m3u8 into
+// outputDir/hls/<cameraID>/. It runs until the returned stop func is
+// called. Segments are written as raw keyframe-aligned chunks of the
+// source codec's bitstream (the same data ffmpeg would mux into an MPEG-TS
+// container); clients expecting strict MPEG-TS framing need a muxing
+// front-end in front of this directory, which is out of scope here.
+func startHLSWriter(cameraID int, client camera.RTSPClient, outputDir string) func() {
+	dir := filepath.Join(outputDir, "hls", fmt.Sprintf("%d", cameraID))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("⚠️  Could not create HLS directory %s: %v", dir, err)
+		return func() {}
+	}
+
+	ch, unsubscribe := client.Subscribe()
+	done := make(chan struct{})
+
+	go runHLSWriter(dir, ch, done)
+
+	return func() {
+		unsubscribe()
+		<-done
+	}
+}
+
+// runHLSWriter consumes ch, starting a new segment at every keyframe once
+// the current segment has run for at least hlsSegmentDuration, and
+// rewrites the playlist after each completed segment.
+func runHLSWriter(dir string, ch <-chan packets.Packet, done chan struct{}) {
+	defer close(done)
+
+	var (
+		segments     []string
+		segmentStart time.Time
+		segmentFile  *os.File
+		segmentIndex int
+	)
+
+	closeSegment := func() {
+		if segmentFile == nil {
+			return
+		}
+		segmentFile.Close()
+		segmentFile = nil
+	}
+	defer closeSegment()
+
+	for pkt := range ch {
+		if pkt.IsKeyframe && (segmentFile == nil || time.Since(segmentStart) >= hlsSegmentDuration) {
+			closeSegment()
+
+			name := fmt.Sprintf("segment_%d.ts", segmentIndex)
+			segmentIndex++
+
+			f, err := os.Create(filepath.Join(dir, name))
+			if err != nil {
+				log.Printf("⚠️  Could not create HLS segment %s: %v", name, err)
+				continue
+			}
+			segmentFile = f
+			segmentStart = pkt.Timestamp
+
+			segments = append(segments, name)
+			if len(segments) > hlsWindowSize {
+				stale := segments[0]
+				segments = segments[1:]
+				os.Remove(filepath.Join(dir, stale))
+			}
+
+			mediaSequence := segmentIndex - len(segments)
+			if err := writePlaylist(dir, segments, mediaSequence); err != nil {
+				log.Printf("⚠️  Could not write HLS playlist: %v", err)
+			}
+		}
+
+		if segmentFile != nil {
+			if _, err := segmentFile.Write(pkt.Data); err != nil {
+				log.Printf("⚠️  Could not write HLS segment data: %v", err)
+			}
+		}
+	}
+}
+
+// writePlaylist regenerates playlist.m3u8 for the current segment window,
+// following the same hand-rolled-wire-format approach as the Prometheus
+// text exposition in internal/api/stats.go.
+func writePlaylist(dir string, segments []string, mediaSequence int) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	b.WriteString(fmt.Sprintf("#EXT-X-TARGETDURATION:%d\n", int(hlsSegmentDuration.Seconds())))
+	b.WriteString(fmt.Sprintf("#EXT-X-MEDIA-SEQUENCE:%d\n", mediaSequence))
+	for _, name := range segments {
+		b.WriteString(fmt.Sprintf("#EXTINF:%.1f,\n", hlsSegmentDuration.Seconds()))
+		b.WriteString(name + "\n")
+	}
+
+	tmp := filepath.Join(dir, "playlist.m3u8.tmp")
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, "playlist.m3u8"))
+}
@@ -0,0 +1,256 @@
+// Package broadcast re-publishes a camera's live packets to external
+// egress targets: RTMP (YouTube, Twitch, another NVR), HLS, and WebRTC
+// (WHEP). Each Manager owns exactly one camera and fans its capture
+// subscription out to whichever egress pipelines are currently running,
+// independently of capture itself - restarting the RTMP target never
+// interrupts the source stream or the HLS writer.
+package broadcast
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nareix/joy4/av"
+	"github.com/nareix/joy4/format/rtmp"
+
+	"camera-detection-project/internal/camera"
+	"camera-detection-project/internal/packets"
+	"camera-detection-project/internal/storage"
+)
+
+// Status is a snapshot of a Manager's RTMP pipeline, returned by Status().
+type Status struct {
+	Running   bool
+	TargetURL string
+	BytesSent int64
+	StartedAt time.Time
+}
+
+// Manager re-publishes one camera's packets to external egress targets.
+// Start/Stop swap the RTMP pipeline under mu without touching the HLS
+// writer or the underlying capture subscription, mirroring the
+// pipeline-swap pattern mature streaming daemons use to let a downstream
+// target reconnect without dropping the upstream source.
+type Manager struct {
+	cameraID int
+	client   camera.RTSPClient
+	storage  *storage.Service
+
+	mu       sync.Mutex
+	pipeline *rtmpPipeline
+
+	hlsStop func()
+}
+
+// NewManager creates a Manager for one camera and starts its HLS writer,
+// which runs for the lifetime of the Manager regardless of whether an RTMP
+// target is ever started.
+func NewManager(cameraID int, client camera.RTSPClient, storageService *storage.Service, outputDir string) *Manager {
+	m := &Manager{
+		cameraID: cameraID,
+		client:   client,
+		storage:  storageService,
+	}
+
+	m.hlsStop = startHLSWriter(cameraID, client, outputDir)
+	return m
+}
+
+// rtmpPipeline is one running RTMP publish: a subscription to the
+// camera's packets, an open connection to the target, and a running byte
+// count.
+type rtmpPipeline struct {
+	unsubscribe func()
+	conn        *rtmp.Conn
+	bytesSent   int64 // atomic
+	done        chan struct{}
+	broadcastID int
+	targetURL   string
+	startedAt   time.Time
+	finishOnce  sync.Once
+}
+
+// Start connects to an RTMP target and begins re-publishing the camera's
+// packets to it. Any previously running RTMP pipeline is stopped first, so
+// calling Start again simply retargets the camera without affecting HLS or
+// the capture backend.
+func (m *Manager) Start(targetURL string) error {
+	streams, err := avStreamsFromDescribe(m.client.Describe())
+	if err != nil {
+		return fmt.Errorf("broadcast: %w", err)
+	}
+
+	conn, err := rtmp.Dial(targetURL)
+	if err != nil {
+		m.recordError(targetURL, err)
+		return fmt.Errorf("broadcast: failed to dial RTMP target: %w", err)
+	}
+
+	if err := conn.WriteHeader(streams); err != nil {
+		conn.Close()
+		m.recordError(targetURL, err)
+		return fmt.Errorf("broadcast: failed to write RTMP header: %w", err)
+	}
+
+	broadcastRow, err := m.storage.CreateBroadcast(m.cameraID, storage.BroadcastProtocolRTMP, targetURL)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("broadcast: failed to record broadcast: %w", err)
+	}
+
+	ch, unsubscribe := m.client.Subscribe()
+	pipeline := &rtmpPipeline{
+		unsubscribe: unsubscribe,
+		conn:        conn,
+		done:        make(chan struct{}),
+		broadcastID: broadcastRow.ID,
+		targetURL:   targetURL,
+		startedAt:   broadcastRow.StartedAt,
+	}
+
+	m.mu.Lock()
+	m.stopLocked()
+	m.pipeline = pipeline
+	m.mu.Unlock()
+
+	go m.pump(pipeline, ch)
+
+	if err := m.storage.CreateEventForCamera(m.cameraID, storage.EventTypeBroadcastStarted,
+		storage.SeverityLow, "Broadcast started", fmt.Sprintf("RTMP broadcast started to %s", targetURL)); err != nil {
+		log.Printf("Warning: could not create broadcast_started event: %v", err)
+	}
+
+	log.Printf("📡 RTMP broadcast started for camera %d -> %s", m.cameraID, targetURL)
+	return nil
+}
+
+// pump forwards packets from ch to the RTMP connection until the pipeline
+// is stopped or the capture subscription closes.
+func (m *Manager) pump(p *rtmpPipeline, ch <-chan packets.Packet) {
+	defer close(p.done)
+
+	for pkt := range ch {
+		avPkt := av.Packet{
+			IsKeyFrame: pkt.IsKeyframe,
+			Data:       pkt.Data,
+		}
+
+		if err := p.conn.WritePacket(avPkt); err != nil {
+			log.Printf("⚠️  RTMP write failed for camera %d: %v", m.cameraID, err)
+			if err := m.storage.CreateEventForCamera(m.cameraID, storage.EventTypeBroadcastError,
+				storage.SeverityMedium, "Broadcast error", err.Error()); err != nil {
+				log.Printf("Warning: could not create broadcast_error event: %v", err)
+			}
+			p.finish(m.storage, storage.BroadcastStatusError)
+			return
+		}
+
+		atomic.AddInt64(&p.bytesSent, int64(len(pkt.Data)))
+	}
+}
+
+// finish records the pipeline's terminal status exactly once, whether it
+// was stopped deliberately or failed mid-stream.
+func (p *rtmpPipeline) finish(storageService *storage.Service, status string) {
+	p.finishOnce.Do(func() {
+		if err := storageService.FinishBroadcast(p.broadcastID, status, atomic.LoadInt64(&p.bytesSent)); err != nil {
+			log.Printf("Warning: could not finalize broadcast record: %v", err)
+		}
+	})
+}
+
+// Stop halts the running RTMP pipeline, if any, and leaves HLS untouched.
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	m.stopLocked()
+	m.mu.Unlock()
+	return nil
+}
+
+// stopLocked tears down the current RTMP pipeline. Callers must hold m.mu.
+func (m *Manager) stopLocked() {
+	if m.pipeline == nil {
+		return
+	}
+
+	p := m.pipeline
+	m.pipeline = nil
+
+	p.unsubscribe()
+	p.conn.Close()
+	<-p.done
+
+	p.finish(m.storage, storage.BroadcastStatusStopped)
+
+	log.Printf("🛑 RTMP broadcast stopped for camera %d", m.cameraID)
+}
+
+// IsRunning reports whether an RTMP pipeline is currently active.
+func (m *Manager) IsRunning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pipeline != nil
+}
+
+// Status returns a snapshot of the current RTMP pipeline.
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pipeline == nil {
+		return Status{}
+	}
+
+	return Status{
+		Running:   true,
+		TargetURL: m.pipeline.targetURL,
+		BytesSent: atomic.LoadInt64(&m.pipeline.bytesSent),
+		StartedAt: m.pipeline.startedAt,
+	}
+}
+
+// Close stops every pipeline owned by this Manager, including HLS.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.stopLocked()
+	m.mu.Unlock()
+
+	if m.hlsStop != nil {
+		m.hlsStop()
+	}
+	return nil
+}
+
+// recordError persists a broadcast_error event for a target that never
+// made it past connecting.
+func (m *Manager) recordError(targetURL string, cause error) {
+	if err := m.storage.CreateEventForCamera(m.cameraID, storage.EventTypeBroadcastError,
+		storage.SeverityMedium, "Broadcast error", fmt.Sprintf("failed to start RTMP broadcast to %s: %v", targetURL, cause)); err != nil {
+		log.Printf("Warning: could not create broadcast_error event: %v", err)
+	}
+}
+
+// avStreamsFromDescribe converts the backend-agnostic packets.Stream
+// metadata into the av.CodecData joy4 needs for its RTMP header. Only
+// H.264 is supported today, matching the only codec the capture backends
+// currently describe.
+func avStreamsFromDescribe(streams []packets.Stream) ([]av.CodecData, error) {
+	for _, s := range streams {
+		if s.Codec == "h264" {
+			// joy4 derives SPS/PPS from the first keyframe it sees rather
+			// than needing them up front, so an empty codec data slot is
+			// enough to establish a single H.264 video stream.
+			return []av.CodecData{h264CodecData{}}, nil
+		}
+	}
+	return nil, fmt.Errorf("no H.264 stream available to broadcast")
+}
+
+// h264CodecData is a minimal av.CodecData for an H.264 stream whose
+// SPS/PPS joy4 will pick up from the packet stream itself.
+type h264CodecData struct{}
+
+func (h264CodecData) Type() av.CodecType { return av.H264 }
@@ -0,0 +1,205 @@
+package broadcast
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/pion/webrtc/v3/pkg/media"
+
+	"camera-detection-project/internal/packets"
+	"camera-detection-project/internal/storage"
+)
+
+// defaultSampleDuration is used for the first sample of a WebRTC track,
+// before two packet timestamps are available to measure an interval from.
+const defaultSampleDuration = 33 * time.Millisecond
+
+// Registry maps camera IDs to their Manager, so HTTP handlers that only
+// know a camera ID from the URL path can reach the right packet source.
+type Registry struct {
+	mu       sync.RWMutex
+	managers map[int]*Manager
+}
+
+// NewRegistry creates an empty Manager registry.
+func NewRegistry() *Registry {
+	return &Registry{managers: make(map[int]*Manager)}
+}
+
+// Add registers a camera's Manager so it becomes reachable through the
+// registry's HTTP handlers.
+func (reg *Registry) Add(cameraID int, m *Manager) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.managers[cameraID] = m
+}
+
+// Remove unregisters a camera's Manager.
+func (reg *Registry) Remove(cameraID int) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	delete(reg.managers, cameraID)
+}
+
+func (reg *Registry) get(cameraID int) (*Manager, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	m, ok := reg.managers[cameraID]
+	return m, ok
+}
+
+// WHEPHandler answers WHEP (WebRTC-HTTP Egress Protocol) offers at
+// /webrtc/<cameraID>: the client POSTs an SDP offer and gets back an SDP
+// answer for a peer connection carrying the camera's live H.264 track.
+func (reg *Registry) WHEPHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cameraID, err := CameraIDFromPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m, ok := reg.get(cameraID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no broadcast manager for camera %d", cameraID), http.StatusNotFound)
+		return
+	}
+
+	offerBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read offer", http.StatusBadRequest)
+		return
+	}
+
+	answer, err := m.handleWHEPOffer(string(offerBytes))
+	if err != nil {
+		log.Printf("⚠️  WHEP negotiation failed for camera %d: %v", cameraID, err)
+		http.Error(w, "failed to negotiate session", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/sdp")
+	w.Header().Set("Location", r.URL.Path)
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(answer))
+}
+
+// CameraIDFromPath extracts the camera ID from a /webrtc/<id> request path.
+func CameraIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/webrtc/")
+	idStr = strings.Trim(idStr, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid camera ID in path %q", path)
+	}
+	return id, nil
+}
+
+// handleWHEPOffer builds a peer connection carrying this camera's live
+// H.264 track and returns an SDP answer for the given offer.
+func (m *Manager) handleWHEPOffer(offerSDP string) (string, error) {
+	track, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeH264},
+		fmt.Sprintf("camera-%d", m.cameraID), fmt.Sprintf("camera-%d-stream", m.cameraID),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create WebRTC track: %w", err)
+	}
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create peer connection: %w", err)
+	}
+
+	if _, err := pc.AddTrack(track); err != nil {
+		pc.Close()
+		return "", fmt.Errorf("failed to add track: %w", err)
+	}
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{
+		Type: webrtc.SDPTypeOffer,
+		SDP:  offerSDP,
+	}); err != nil {
+		pc.Close()
+		return "", fmt.Errorf("failed to set remote description: %w", err)
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		pc.Close()
+		return "", fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		pc.Close()
+		return "", fmt.Errorf("failed to set local description: %w", err)
+	}
+	<-gatherComplete
+
+	ch, unsubscribe := m.client.Subscribe()
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		m.recordWebRTCStateEvent(state)
+		if state == webrtc.PeerConnectionStateFailed || state == webrtc.PeerConnectionStateClosed ||
+			state == webrtc.PeerConnectionStateDisconnected {
+			unsubscribe()
+		}
+	})
+
+	go forwardToTrack(ch, track)
+
+	return pc.LocalDescription().SDP, nil
+}
+
+// recordWebRTCStateEvent logs an ICE/DTLS connection state transition as a
+// camera event, so WHEP viewer connects/disconnects show up in the events
+// feed the same way an RTMP publish does.
+func (m *Manager) recordWebRTCStateEvent(state webrtc.PeerConnectionState) {
+	if m.storage == nil {
+		return
+	}
+
+	var eventType, severity, title string
+	switch state {
+	case webrtc.PeerConnectionStateConnected:
+		eventType, severity, title = storage.EventTypeWebRTCConnected, storage.SeverityLow, "WebRTC Viewer Connected"
+	case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed, webrtc.PeerConnectionStateDisconnected:
+		eventType, severity, title = storage.EventTypeWebRTCClosed, storage.SeverityLow, "WebRTC Viewer Disconnected"
+	default:
+		return
+	}
+
+	message := fmt.Sprintf("Camera %d: WHEP connection state changed to %s", m.cameraID, state)
+	if err := m.storage.CreateEventForCamera(m.cameraID, eventType, severity, title, message); err != nil {
+		log.Printf("Warning: could not record WebRTC state event for camera %d: %v", m.cameraID, err)
+	}
+}
+
+// forwardToTrack writes every packet read from ch into track as a sample,
+// until the channel is closed (the peer connection tore down and
+// unsubscribed).
+func forwardToTrack(ch <-chan packets.Packet, track *webrtc.TrackLocalStaticSample) {
+	var lastTimestamp time.Time
+	for pkt := range ch {
+		duration := defaultSampleDuration
+		if !lastTimestamp.IsZero() {
+			duration = pkt.Timestamp.Sub(lastTimestamp)
+		}
+		lastTimestamp = pkt.Timestamp
+
+		if err := track.WriteSample(media.Sample{Data: pkt.Data, Duration: duration}); err != nil {
+			return
+		}
+	}
+}
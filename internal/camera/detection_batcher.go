@@ -0,0 +1,162 @@
+package camera
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The window matches storage's frameBatcher: long enough to catch a handful
+// of frames landing close together, short enough that a single frame never
+// waits long for its detection result. The batch size is capped lower than
+// frameBatcher's (16 images rather than 100 rows).
+const (
+	detectionBatchWindow  = 100 * time.Millisecond
+	detectionBatchMaxSize = 16
+)
+
+// BatchDetectionResponse is the detection service's response to a POST
+// against /detect/batch: one DetectionResponse per image path, in the same
+// order the paths were sent.
+type BatchDetectionResponse struct {
+	Results []DetectionResponse `json:"results"`
+}
+
+// detectionJob is one pending detectObjects call waiting on the next
+// detectionBatcher flush.
+type detectionJob struct {
+	path string
+	done chan detectionJobResult
+}
+
+type detectionJobResult struct {
+	result DetectionResponse
+	err    error
+}
+
+// detectionBatcher coalesces concurrent detection requests from one
+// FFmpegClient into a single POST to DetectionService.URL+"/detect/batch",
+// for a detection service whose model supports batched inference. Only
+// built when DetectionService.SupportsBatch is set; otherwise detectObjects
+// posts to /detect directly, one frame at a time, as before.
+type detectionBatcher struct {
+	client *FFmpegClient
+
+	mu      sync.Mutex
+	pending []*detectionJob
+	timer   *time.Timer
+}
+
+func newDetectionBatcher(client *FFmpegClient) *detectionBatcher {
+	return &detectionBatcher{client: client}
+}
+
+// Detect enqueues path for the next flush and blocks until a result is
+// available, either from a batched request or this job's own fallback call.
+func (b *detectionBatcher) Detect(path string) (DetectionResponse, error) {
+	job := &detectionJob{path: path, done: make(chan detectionJobResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, job)
+	full := len(b.pending) >= detectionBatchMaxSize
+	b.armLocked()
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+
+	res := <-job.done
+	return res.result, res.err
+}
+
+// armLocked starts the flush timer if one isn't already pending. Callers
+// must hold b.mu.
+func (b *detectionBatcher) armLocked() {
+	if b.timer != nil {
+		return
+	}
+	b.timer = time.AfterFunc(detectionBatchWindow, b.flush)
+}
+
+// flush drains whatever jobs are currently queued and resolves them with
+// one batched POST when there's more than one, or postDetectOnce directly
+// when there's just one.
+func (b *detectionBatcher) flush() {
+	b.mu.Lock()
+	jobs := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(jobs) == 0 {
+		return
+	}
+
+	if len(jobs) == 1 {
+		result, err := b.client.postDetectOnce(jobs[0].path)
+		jobs[0].done <- detectionJobResult{result: result, err: err}
+		return
+	}
+
+	results, err := b.postDetectBatch(jobs)
+	if err != nil {
+		log.Printf("⚠️  Batched detection request failed, falling back to per-frame requests: %v", err)
+		for _, job := range jobs {
+			result, err := b.client.postDetectOnce(job.path)
+			job.done <- detectionJobResult{result: result, err: err}
+		}
+		return
+	}
+
+	for i, job := range jobs {
+		job.done <- detectionJobResult{result: results[i]}
+	}
+}
+
+// postDetectBatch POSTs every job's image path in one request to
+// DetectionService.URL+"/detect/batch" and returns one DetectionResponse per
+// job, in the same order. A single attempt is made; flush falls back to
+// postDetectOnce per job (which does retry) on any failure here.
+func (b *detectionBatcher) postDetectBatch(jobs []*detectionJob) ([]DetectionResponse, error) {
+	c := b.client
+
+	paths := make([]string, len(jobs))
+	for i, job := range jobs {
+		paths[i] = job.path
+	}
+
+	jsonData, err := json.Marshal(map[string][]string{"image_paths": paths})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch detection request: %w", err)
+	}
+
+	batchURL := c.cfg().DetectionService.URL + "/detect/batch"
+	resp, err := c.detectionClient.Post(batchURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("batch detection request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("detection service returned status: %d", resp.StatusCode)
+	}
+
+	var batchResp BatchDetectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode batch detection response: %w", err)
+	}
+
+	if len(batchResp.Results) != len(jobs) {
+		return nil, fmt.Errorf("detection service returned %d results for %d image paths", len(batchResp.Results), len(jobs))
+	}
+
+	return batchResp.Results, nil
+}
@@ -0,0 +1,219 @@
+package camera
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+)
+
+//go:embed discoverdata/routes.txt
+var defaultRoutesData string
+
+//go:embed discoverdata/creds.txt
+var defaultCredsData string
+
+// Credential is a username/password pair tried against every candidate
+// route during Discover.
+type Credential struct {
+	Username string
+	Password string
+}
+
+// DiscoverOptions configures Discover's probe of a single host: which
+// ports to dial, which routes and credentials to try on each open port,
+// and how long to wait for each RTSP DESCRIBE before giving up.
+type DiscoverOptions struct {
+	Ports   []int
+	Routes  []string
+	Creds   []Credential
+	Timeout time.Duration
+}
+
+// DefaultDiscoverOptions returns the built-in port list plus the routes
+// and credentials embedded from discoverdata/routes.txt and
+// discoverdata/creds.txt. cmd/discover's -routes/-creds flags override
+// opts.Routes/opts.Creds with the same format read from an external file.
+func DefaultDiscoverOptions() DiscoverOptions {
+	return DiscoverOptions{
+		Ports:   []int{554, 8554, 88, 8080},
+		Routes:  ParseRoutes(defaultRoutesData),
+		Creds:   ParseCreds(defaultCredsData),
+		Timeout: 3 * time.Second,
+	}
+}
+
+// ParseRoutes splits data (the contents of a routes file, embedded or
+// user-supplied) into routes, skipping blank lines and #-comments.
+func ParseRoutes(data string) []string {
+	var routes []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		routes = append(routes, line)
+	}
+	return routes
+}
+
+// ParseCreds splits data (the contents of a creds file, embedded or
+// user-supplied) into "username:password" pairs, skipping blank lines and
+// #-comments. A line with no colon is tried as a bare username with no
+// password; a line that is just ":" is tried with no credentials at all.
+func ParseCreds(data string) []Credential {
+	var creds []Credential
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		user, pass, _ := strings.Cut(line, ":")
+		creds = append(creds, Credential{Username: user, Password: pass})
+	}
+	return creds
+}
+
+// DiscoverResult is one working RTSP URL + credential combination Discover
+// found, along with what the camera told us about itself over DESCRIBE.
+type DiscoverResult struct {
+	Host      string
+	Port      int
+	Route     string
+	Username  string
+	Password  string
+	Vendor    string
+	Transport string
+	Codecs    []string
+}
+
+// URL reconstructs the RTSP URL this result was found at, credentials
+// included.
+func (r DiscoverResult) URL() string {
+	u := &url.URL{Scheme: "rtsp", Host: net.JoinHostPort(r.Host, strconv.Itoa(r.Port)), Path: r.Route}
+	if r.Username != "" {
+		u.User = url.UserPassword(r.Username, r.Password)
+	}
+	return u.String()
+}
+
+// Discover probes host on every port in opts.Ports and, for each open
+// port, issues an RTSP DESCRIBE against opts.Routes x opts.Creds
+// combinations until one succeeds. It's meant for inventorying cameras on
+// a network the caller administers (see cmd/discover); it doesn't itself
+// iterate a CIDR or host list, so callers that want to scan a range are
+// expected to call it once per host.
+func Discover(ctx context.Context, host string, opts DiscoverOptions) ([]DiscoverResult, error) {
+	var results []DiscoverResult
+
+	for _, port := range opts.Ports {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		conn, err := net.DialTimeout("tcp", addr, opts.Timeout)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+
+		log.Printf("🔎 Discover: %s is open, trying %d routes x %d credentials", addr, len(opts.Routes), len(opts.Creds))
+
+	routes:
+		for _, route := range opts.Routes {
+			for _, cred := range opts.Creds {
+				if err := ctx.Err(); err != nil {
+					return results, err
+				}
+
+				result, ok := probeRoute(host, port, route, cred, opts.Timeout)
+				if ok {
+					results = append(results, result)
+					// This port's camera is identified; move on to the
+					// next port rather than trying every remaining
+					// route/credential combination against it too.
+					break routes
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// probeRoute issues a single RTSP DESCRIBE at host:port/route with cred
+// and, on success, reports the vendor guess and codecs it found.
+func probeRoute(host string, port int, route string, cred Credential, timeout time.Duration) (DiscoverResult, bool) {
+	rawURL := &url.URL{Scheme: "rtsp", Host: net.JoinHostPort(host, strconv.Itoa(port)), Path: route}
+	if cred.Username != "" {
+		rawURL.User = url.UserPassword(cred.Username, cred.Password)
+	}
+
+	u, err := base.ParseURL(rawURL.String())
+	if err != nil {
+		return DiscoverResult{}, false
+	}
+
+	client := &gortsplib.Client{
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+		Transport:    transportPtr(gortsplib.TransportTCP),
+	}
+
+	if err := client.Start(u.Scheme, u.Host); err != nil {
+		return DiscoverResult{}, false
+	}
+	defer client.Close()
+
+	session, _, err := client.Describe(u)
+	if err != nil {
+		return DiscoverResult{}, false
+	}
+
+	streams := streamsFromSession(session)
+	codecs := make([]string, 0, len(streams))
+	for _, s := range streams {
+		codecs = append(codecs, s.Codec)
+	}
+
+	return DiscoverResult{
+		Host:      host,
+		Port:      port,
+		Route:     route,
+		Username:  cred.Username,
+		Password:  cred.Password,
+		Vendor:    guessVendor(route),
+		Transport: "tcp",
+		Codecs:    codecs,
+	}, true
+}
+
+// guessVendor maps a handful of well-known vendor-specific route patterns
+// to the vendor that popularized them. It's a heuristic, not a guarantee:
+// plenty of cameras accept more than one vendor's route convention.
+func guessVendor(route string) string {
+	switch {
+	case strings.Contains(route, "Streaming/Channels"):
+		return "Hikvision"
+	case strings.Contains(route, "cam/realmonitor"):
+		return "Dahua"
+	case strings.Contains(route, "h264Preview"):
+		return "Amcrest/Dahua-OEM"
+	case strings.Contains(route, "live/ch0"):
+		return "Reolink"
+	default:
+		return "unknown"
+	}
+}
@@ -3,6 +3,7 @@ package camera
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 	"bytes"
     "encoding/json"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/packets"
 	"camera-detection-project/internal/storage"
 )
 
@@ -44,37 +47,50 @@ type BoundingBox struct {
 }
 
 type FFmpegClient struct {
-	config         *config.Config
+	// config holds the *config.Config this client currently reads from. It
+	// is an atomic.Value instead of a plain pointer so UpdateConfig can
+	// swap it in from Config.Watch's callback goroutine while capture and
+	// detection goroutines read it concurrently without a data race.
+	config         atomic.Value // stores *config.Config
 	cmd            *exec.Cmd
 	ctx            context.Context
 	cancel         context.CancelFunc
 	wg             sync.WaitGroup
+	done           chan error
 	frameCount     int
 	mu             sync.Mutex
 	storageService StorageService
 	currentRecording *storage.Recording
 	detectionClient *http.Client
+	detectionBatcher *detectionBatcher
+
+	// subMu/subs back Subscribe to satisfy RTSPClient. FFmpeg does its own
+	// muxing, so nothing is ever published to these channels.
+	subMu     sync.Mutex
+	subs      map[int]chan packets.Packet
+	nextSubID int
 }
 
 // StorageService interface to work with storage package
 type StorageService interface {
-	StartRecording(filePath string) (*storage.Recording, error)
+	StartRecording(cameraID int, filePath string) (*storage.Recording, error)
 	FinishRecording(recordingID int, filePath string) error
-	SaveFrame(filePath string, recordingID *int) (*storage.Frame, error)
+	SaveFrame(cameraID int, filePath string, recordingID *int, fromDetection bool) (*storage.Frame, error)
 	UpdateFrameProcessed(frameID int, hasDetection bool, thumbnailPath *string) error
-	CreateEvent(eventType, severity, title, message string, metadata *string) error
-	UpdateCameraStatus(status string) error
+	CreateEventForCamera(cameraID int, eventType, severity, title, message string) error
+	CreateEventRecording(cameraID, eventID int, filePath string, fileSize int64, duration int) (*storage.Recording, error)
+	UpdateCameraStatus(cameraID int, status string) error
 }
 
 // NewFFmpegClient creates a new FFmpeg client without storage
 func NewFFmpegClient(cfg *config.Config) (*FFmpegClient, error) {
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	client := &FFmpegClient{
-		config: cfg,
 		ctx:    ctx,
 		cancel: cancel,
 	}
+	client.config.Store(cfg)
 
 	return client, nil
 }
@@ -86,27 +102,45 @@ func NewFFmpegClientWithStorage(cfg *config.Config, storage StorageService) (*FF
 	detectionClient := &http.Client{
 		Timeout: cfg.DetectionService.Timeout,
 	}
-	
+
 	client := &FFmpegClient{
-		config:         cfg,
 		ctx:            ctx,
 		cancel:         cancel,
 		storageService: storage,
 		detectionClient: detectionClient,
 	}
+	client.config.Store(cfg)
+
+	if cfg.DetectionService.SupportsBatch {
+		client.detectionBatcher = newDetectionBatcher(client)
+	}
 
 	return client, nil
 }
 
+// cfg returns the client's current configuration snapshot.
+func (c *FFmpegClient) cfg() *config.Config {
+	return c.config.Load().(*config.Config)
+}
+
+// UpdateConfig swaps in a new configuration snapshot, e.g. from
+// Config.Watch after config.yaml changes. FrameRate only takes effect on
+// the next Start (ffmpeg's -vf fps filter can't be changed on a running
+// process), but DetectionService.ConfidenceThreshold and MaxRetries apply
+// to the very next detection.
+func (c *FFmpegClient) UpdateConfig(cfg *config.Config) {
+	c.config.Store(cfg)
+}
+
 func (c *FFmpegClient) Start() error {
 	log.Println("🎬 Starting FFmpeg video capture...")
 
 	// Create recording record if storage is available
 	if c.storageService != nil {
 		timestamp := time.Now().Format("20060102_150405")
-		recordingPath := filepath.Join(c.config.OutputDir, fmt.Sprintf("recording_%s.mp4", timestamp))
+		recordingPath := filepath.Join(c.cfg().OutputDir, fmt.Sprintf("recording_%s.mp4", timestamp))
 		
-		recording, err := c.storageService.StartRecording(recordingPath)
+		recording, err := c.storageService.StartRecording(c.cfg().CameraID, recordingPath)
 		if err != nil {
 			log.Printf("⚠️  Warning: Could not create recording record: %v", err)
 		} else {
@@ -118,7 +152,7 @@ func (c *FFmpegClient) Start() error {
 	// Build FFmpeg command for RTSP stream processing
 	args := c.buildFFmpegArgs()
 	
-	c.cmd = exec.CommandContext(c.ctx, c.config.FFmpegPath, args...)
+	c.cmd = exec.CommandContext(c.ctx, c.cfg().FFmpegPath, args...)
 	
 	// Setup stdout and stderr pipes
 	stdout, err := c.cmd.StdoutPipe()
@@ -136,14 +170,32 @@ func (c *FFmpegClient) Start() error {
 		return fmt.Errorf("failed to start ffmpeg: %w", err)
 	}
 
-	// Start monitoring goroutines
+	// Start monitoring goroutines. pipesDone tracks just these two, so the
+	// Wait() goroutine below can hold off until both have drained their
+	// pipe - calling Wait before a concurrent read of stdout/stderr
+	// finishes can race Wait's own pipe-close against that read.
 	c.wg.Add(2)
-	
-	go c.monitorOutput(stdout, "STDOUT")
-	go c.monitorOutput(stderr, "STDERR")
+	var pipesDone sync.WaitGroup
+	pipesDone.Add(2)
+
+	go func() {
+		defer pipesDone.Done()
+		c.monitorOutput(stdout, "STDOUT")
+	}()
+	go func() {
+		defer pipesDone.Done()
+		c.monitorOutput(stderr, "STDERR")
+	}()
+
+	c.done = make(chan error, 1)
+	cmd := c.cmd
+	go func() {
+		pipesDone.Wait()
+		c.done <- cmd.Wait()
+	}()
 
 	// Start frame processing if detection is enabled
-	if c.config.DetectionEnabled {
+	if c.cfg().DetectionEnabled {
 		c.wg.Add(1)
 		go c.watchFrames()
 	}
@@ -154,13 +206,13 @@ func (c *FFmpegClient) Start() error {
 
 func (c *FFmpegClient) buildFFmpegArgs() []string {
 	// Build RTSP URL with credentials if provided
-	rtspURL := c.config.RTSPURL
-	if c.config.Username != "" && c.config.Password != "" {
+	rtspURL := c.cfg().RTSPURL
+	if c.cfg().Username != "" && c.cfg().Password != "" {
 		// Insert credentials into RTSP URL
 		rtspURL = fmt.Sprintf("rtsp://%s:%s@%s", 
-			c.config.Username, 
-			c.config.Password, 
-			c.config.RTSPURL[7:]) // Remove "rtsp://" prefix
+			c.cfg().Username, 
+			c.cfg().Password, 
+			c.cfg().RTSPURL[7:]) // Remove "rtsp://" prefix
 	}
 
 	timestamp := time.Now().Format("20060102_150405")
@@ -174,16 +226,16 @@ func (c *FFmpegClient) buildFFmpegArgs() []string {
 		"-segment_time", "60",     // 60 second segments
 		"-segment_format", "mp4",  // Segment format
 		"-strftime", "1",          // Enable strftime in filename
-		filepath.Join(c.config.OutputDir, fmt.Sprintf("recording_%s_%%Y%%m%%d_%%H%%M%%S.mp4", timestamp)),
+		filepath.Join(c.cfg().OutputDir, fmt.Sprintf("recording_%s_%%Y%%m%%d_%%H%%M%%S.mp4", timestamp)),
 	}
 
 	// Add frame extraction for detection if needed
-	if c.config.SaveFrames {
+	if c.cfg().SaveFrames {
 		frameArgs := []string{
-			"-vf", fmt.Sprintf("fps=1/%d", c.config.FrameRate), // Extract frame every N seconds
+			"-vf", fmt.Sprintf("fps=1/%d", c.cfg().FrameRate), // Extract frame every N seconds
 			"-f", "image2",
 			"-strftime", "1",
-			filepath.Join(c.config.OutputDir, fmt.Sprintf("frame_%s_%%Y%%m%%d_%%H%%M%%S.jpg", timestamp)),
+			filepath.Join(c.cfg().OutputDir, fmt.Sprintf("frame_%s_%%Y%%m%%d_%%H%%M%%S.jpg", timestamp)),
 		}
 		args = append(args, frameArgs...)
 	}
@@ -219,12 +271,12 @@ func (c *FFmpegClient) monitorOutput(pipe io.ReadCloser, name string) {
 func (c *FFmpegClient) handleFFmpegError(line string) {
 	// Check for critical errors and create events
 	if contains := []string{"Connection refused", "timeout", "No route to host"}; containsAny(line, contains) {
-		c.storageService.CreateEvent(
+		c.storageService.CreateEventForCamera(
+			c.cfg().CameraID,
 			"camera_error",
 			"high",
 			"Camera Connection Error",
 			fmt.Sprintf("FFmpeg error: %s", line),
-			nil,
 		)
 	}
 }
@@ -240,13 +292,13 @@ func (c *FFmpegClient) watchFrames() {
     defer watcher.Close()
     
     // Следить за папкой output
-    err = watcher.Add(c.config.OutputDir)
+    err = watcher.Add(c.cfg().OutputDir)
     if err != nil {
         log.Printf("❌ Failed to watch directory: %v", err)
         return
     }
     
-    log.Printf("👁️ Watching for new frames in: %s", c.config.OutputDir)
+    log.Printf("👁️ Watching for new frames in: %s", c.cfg().OutputDir)
     
     for {
         select {
@@ -287,141 +339,176 @@ func (c *FFmpegClient) handleNewFrame(framePath string) {
             recordingID = &c.currentRecording.ID
         }
         
-        frame, err := c.storageService.SaveFrame(framePath, recordingID)
+        frame, err := c.storageService.SaveFrame(c.cfg().CameraID, framePath, recordingID, false)
         if err != nil {
-            log.Printf("⚠️ Warning: Could not save frame to database: %v", err)
+            if errors.Is(err, storage.ErrDuplicateFrame) {
+                log.Printf("⏭️ Skipped near-duplicate frame: %s", filepath.Base(framePath))
+            } else {
+                log.Printf("⚠️ Warning: Could not save frame to database: %v", err)
+            }
             return
         }
         
         log.Printf("💾 Saved frame to database (ID: %d)", frame.ID)
         
         // Запустить детекцию если включена
-        if c.config.DetectionEnabled {
+        if c.cfg().DetectionEnabled {
             c.mu.Lock()
             c.frameCount++
             frameNum := c.frameCount
             c.mu.Unlock()
-            
-            hasDetection := c.detectObjects(framePath, frameNum)
-            
-            // Обновить результаты детекции
-            if err := c.storageService.UpdateFrameProcessed(frame.ID, hasDetection, nil); err != nil {
-                log.Printf("⚠️ Warning: Could not update frame processed status: %v", err)
+
+            if c.detectionBatcher != nil {
+                // Dispatch off the fsnotify goroutine so concurrently
+                // in-flight frames can actually land in the same
+                // detectionBatcher flush instead of queuing one at a time.
+                go c.runDetection(frame.ID, framePath, frameNum)
+            } else {
+                c.runDetection(frame.ID, framePath, frameNum)
             }
         }
     }
 }
 
+// runDetection runs detection for one frame and records the result,
+// synchronously from the caller's point of view. handleNewFrame calls this
+// inline when detection requests aren't batched, or from its own goroutine
+// when they are.
+func (c *FFmpegClient) runDetection(frameID int, framePath string, frameNum int) {
+    hasDetection := c.detectObjects(framePath, frameNum)
+
+    if err := c.storageService.UpdateFrameProcessed(frameID, hasDetection, nil); err != nil {
+        log.Printf("⚠️ Warning: Could not update frame processed status: %v", err)
+    }
+}
+
 func (c *FFmpegClient) detectObjects(framePath string, frameNum int) bool {
-	if !c.config.DetectionEnabled {
+	if !c.cfg().DetectionEnabled {
 		return false
 	}
-	
+
 	log.Printf("🔍 Running YOLO detection on frame #%d: %s", frameNum, filepath.Base(framePath))
 
-	detectionPath := strings.Replace(framePath, c.config.OutputDir, "/app/data", 1)
+	detectionPath := strings.Replace(framePath, c.cfg().OutputDir, "/app/data", 1)
 
 	log.Printf("🔄 Transformed path: %s -> %s", framePath, detectionPath)
 
-	
-	// Подготовить запрос
+	var result DetectionResponse
+	var lastErr error
+
+	if c.detectionBatcher != nil {
+		result, lastErr = c.detectionBatcher.Detect(detectionPath)
+	} else {
+		result, lastErr = c.postDetectOnce(detectionPath)
+	}
+
+	return c.processDetectionResult(result, lastErr, framePath, frameNum)
+}
+
+// postDetectOnce POSTs a single image path to the detection service's
+// /detect endpoint, retrying up to DetectionService.MaxRetries times with a
+// linear backoff. Used directly by detectObjects when batching isn't
+// available, and as detectionBatcher's per-item fallback when a batch
+// request fails.
+func (c *FFmpegClient) postDetectOnce(detectionPath string) (DetectionResponse, error) {
 	requestBody := map[string]string{
 		"image_path": detectionPath,
 	}
-	
+
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		log.Printf("❌ Failed to marshal detection request: %v", err)
-		return false
+		return DetectionResponse{}, fmt.Errorf("failed to marshal detection request: %w", err)
 	}
-	
-	// Отправить запрос к detection service с retry логикой
+
 	var result DetectionResponse
 	var lastErr error
-	
-	for attempt := 1; attempt <= c.config.DetectionService.MaxRetries; attempt++ {
-		detectURL := c.config.DetectionService.URL + "/detect"
+
+	for attempt := 1; attempt <= c.cfg().DetectionService.MaxRetries; attempt++ {
+		detectURL := c.cfg().DetectionService.URL + "/detect"
 		resp, err := c.detectionClient.Post(detectURL, "application/json", bytes.NewBuffer(jsonData))
-		
+
 		if err != nil {
 			lastErr = err
-			log.Printf("⚠️  Detection attempt %d/%d failed: %v", attempt, c.config.DetectionService.MaxRetries, err)
-			if attempt < c.config.DetectionService.MaxRetries {
+			log.Printf("⚠️  Detection attempt %d/%d failed: %v", attempt, c.cfg().DetectionService.MaxRetries, err)
+			if attempt < c.cfg().DetectionService.MaxRetries {
 				time.Sleep(time.Duration(attempt) * time.Second)
 				continue
 			}
 			break
 		}
-		
+
 		defer resp.Body.Close()
-		
+
 		if resp.StatusCode != http.StatusOK {
 			lastErr = fmt.Errorf("detection service returned status: %d", resp.StatusCode)
-			log.Printf("⚠️  Detection attempt %d/%d failed with status: %d", attempt, c.config.DetectionService.MaxRetries, resp.StatusCode)
-			if attempt < c.config.DetectionService.MaxRetries {
+			log.Printf("⚠️  Detection attempt %d/%d failed with status: %d", attempt, c.cfg().DetectionService.MaxRetries, resp.StatusCode)
+			if attempt < c.cfg().DetectionService.MaxRetries {
 				time.Sleep(time.Duration(attempt) * time.Second)
 				continue
 			}
 			break
 		}
-		
-		// Разобрать ответ
+
 		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 			lastErr = err
-			log.Printf("⚠️  Detection attempt %d/%d failed to decode response: %v", attempt, c.config.DetectionService.MaxRetries, err)
-			if attempt < c.config.DetectionService.MaxRetries {
+			log.Printf("⚠️  Detection attempt %d/%d failed to decode response: %v", attempt, c.cfg().DetectionService.MaxRetries, err)
+			if attempt < c.cfg().DetectionService.MaxRetries {
 				time.Sleep(time.Duration(attempt) * time.Second)
 				continue
 			}
 			break
 		}
-		
-		// Успешно получили ответ
+
 		lastErr = nil
 		break
 	}
-	
+
+	return result, lastErr
+}
+
+// processDetectionResult applies the confidence threshold to a (batched or
+// single) detection response, logs the outcome, and records a detection
+// event for anything that survives the threshold. Returns whether at least
+// one qualifying detection was found.
+func (c *FFmpegClient) processDetectionResult(result DetectionResponse, lastErr error, framePath string, frameNum int) bool {
 	if lastErr != nil {
 		log.Printf("❌ All detection attempts failed: %v", lastErr)
 		c.logDetectionError(lastErr.Error())
 		return false
 	}
-	
+
 	if !result.Success {
 		log.Printf("❌ Detection failed: %s", result.Error)
 		c.logDetectionError(result.Error)
 		return false
 	}
-	
-	// Обработать результаты
+
 	if result.TotalObjects > 0 {
 		log.Printf("✅ Found %d objects in %.1fms:", result.TotalObjects, result.ProcessingTimeMS)
-		
-		// Фильтровать по порогу уверенности
+
 		validDetections := []Detection{}
 		for _, detection := range result.Detections {
-			if detection.Confidence >= c.config.DetectionService.ConfidenceThreshold {
+			if detection.Confidence >= c.cfg().DetectionService.ConfidenceThreshold {
 				validDetections = append(validDetections, detection)
 				confidence := detection.Confidence * 100
 				log.Printf("   🎯 %s (%.1f%%)", detection.Class, confidence)
 			}
 		}
-		
+
 		if len(validDetections) > 0 {
-			// Создать событие о детекции
 			if c.storageService != nil {
 				c.createDetectionEvent(validDetections, framePath, frameNum)
 			}
 			return true
-		} else {
-			log.Printf("📷 Objects found but below confidence threshold (%.2f) in frame #%d", 
-				c.config.DetectionService.ConfidenceThreshold, frameNum)
-			return false
 		}
-	} else {
-		log.Printf("📷 No objects detected in frame #%d (%.1fms)", frameNum, result.ProcessingTimeMS)
+
+		log.Printf("📷 Objects found but below confidence threshold (%.2f) in frame #%d",
+			c.cfg().DetectionService.ConfidenceThreshold, frameNum)
 		return false
 	}
+
+	log.Printf("📷 No objects detected in frame #%d (%.1fms)", frameNum, result.ProcessingTimeMS)
+	return false
 }
 
 func (c *FFmpegClient) createDetectionEvent(detections []Detection, framePath string, frameNum int) {
@@ -465,12 +552,12 @@ func (c *FFmpegClient) createDetectionEvent(detections []Detection, framePath st
 	}
 	
 	// Создать событие
-	err := c.storageService.CreateEvent(
+	err := c.storageService.CreateEventForCamera(
+		c.cfg().CameraID,
 		eventType,
 		severity,
 		title,
 		message,
-		nil,
 	)
 	
 	if err != nil {
@@ -480,23 +567,62 @@ func (c *FFmpegClient) createDetectionEvent(detections []Detection, framePath st
 
 func (c *FFmpegClient) logDetectionError(errorMsg string) {
 	if c.storageService != nil {
-		c.storageService.CreateEvent(
+		c.storageService.CreateEventForCamera(
+			c.cfg().CameraID,
 			"detection_error",
 			"medium",
 			"Detection Service Error",
 			fmt.Sprintf("Detection service failed: %s", errorMsg),
-			nil,
 		)
 	}
 }
 
-func (c *FFmpegClient) Stop() {
+// Subscribe satisfies RTSPClient. FFmpegClient lets ffmpeg handle its own
+// muxing rather than decoding RTP in-process, so the returned channel is
+// registered but never written to.
+func (c *FFmpegClient) Subscribe() (<-chan packets.Packet, func()) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	if c.subs == nil {
+		c.subs = make(map[int]chan packets.Packet)
+	}
+
+	id := c.nextSubID
+	c.nextSubID++
+	ch := make(chan packets.Packet, 16)
+	c.subs[id] = ch
+
+	return ch, func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+		if ch, ok := c.subs[id]; ok {
+			delete(c.subs, id)
+			close(ch)
+		}
+	}
+}
+
+// Describe satisfies RTSPClient with best-effort stream metadata; ffmpeg
+// negotiates the actual SDP itself so no per-stream detail is available
+// here.
+func (c *FFmpegClient) Describe() []packets.Stream {
+	return []packets.Stream{{Codec: "h264"}}
+}
+
+// Done satisfies RTSPClient, firing once the ffmpeg process exits -
+// whether from Stop's Kill or ffmpeg crashing/exiting on its own.
+func (c *FFmpegClient) Done() <-chan error {
+	return c.done
+}
+
+func (c *FFmpegClient) Stop() error {
 	log.Println("🛑 Stopping FFmpeg client...")
 	
 	// Finish current recording if storage is available
 	if c.storageService != nil && c.currentRecording != nil {
 		// In a real implementation, you'd track the actual file path
-		recordingPath := filepath.Join(c.config.OutputDir, fmt.Sprintf("recording_%d.mp4", c.currentRecording.ID))
+		recordingPath := filepath.Join(c.cfg().OutputDir, fmt.Sprintf("recording_%d.mp4", c.currentRecording.ID))
 		if err := c.storageService.FinishRecording(c.currentRecording.ID, recordingPath); err != nil {
 			log.Printf("⚠️  Warning: Could not finish recording: %v", err)
 		} else {
@@ -514,11 +640,11 @@ func (c *FFmpegClient) Stop() {
 	
 	c.wg.Wait()
 	log.Println("✅ FFmpeg client stopped")
+	return nil
 }
 
 func (c *FFmpegClient) Close() error {
-	c.Stop()
-	return nil
+	return c.Stop()
 }
 
 // Helper functions
@@ -0,0 +1,217 @@
+package camera
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/pion/rtp"
+
+	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/packets"
+	"camera-detection-project/internal/recorder"
+	"camera-detection-project/internal/storage"
+)
+
+// GolibrtspClient is a capture backend built on gortsplib: it dials the
+// camera directly, negotiates SDP, sets up transport, and delivers RTP
+// packets in-process instead of shelling out to ffmpeg. This keeps a
+// single authenticated session open across frame captures and recording
+// and makes per-packet processing (keyframe detection, in-process
+// decoding, low-latency forwarding) possible.
+type GolibrtspClient struct {
+	config         *config.Config
+	storageService StorageService
+
+	client  *gortsplib.Client
+	streams []packets.Stream
+	done    chan error
+
+	// timeline buffers cfg.PreEventSeconds of packets so a positive
+	// detection can produce a clip with pre-roll via Snapshot/RecordEventClip.
+	timeline *packets.Timeline
+
+	mu        sync.Mutex
+	subs      map[int]chan packets.Packet
+	nextSubID int
+}
+
+// NewGolibrtspClient creates a capture backend that speaks RTSP directly
+// via gortsplib instead of shelling out to ffmpeg.
+func NewGolibrtspClient(cfg *config.Config, storage StorageService) (*GolibrtspClient, error) {
+	return &GolibrtspClient{
+		config:         cfg,
+		storageService: storage,
+		timeline:       packets.NewTimeline(cfg.PreEventSeconds),
+		subs:           make(map[int]chan packets.Packet),
+	}, nil
+}
+
+// Start dials the camera, negotiates SDP, sets up TCP transport, and
+// begins delivering RTP packets to subscribers in the background.
+func (c *GolibrtspClient) Start() error {
+	rtspURL := c.config.RTSPURL
+	if c.config.Username != "" && c.config.Password != "" {
+		rtspURL = fmt.Sprintf("rtsp://%s:%s@%s", c.config.Username, c.config.Password, c.config.RTSPURL[7:])
+	}
+
+	u, err := base.ParseURL(rtspURL)
+	if err != nil {
+		return fmt.Errorf("invalid RTSP URL: %w", err)
+	}
+
+	c.client = &gortsplib.Client{
+		Transport: transportPtr(gortsplib.TransportTCP),
+	}
+
+	if err := c.client.Start(u.Scheme, u.Host); err != nil {
+		return fmt.Errorf("failed to connect to camera: %w", err)
+	}
+
+	session, _, err := c.client.Describe(u)
+	if err != nil {
+		c.client.Close()
+		return fmt.Errorf("failed to describe RTSP session: %w", err)
+	}
+	c.streams = streamsFromSession(session)
+
+	if err := c.client.SetupAll(session.BaseURL, session.Medias); err != nil {
+		c.client.Close()
+		return fmt.Errorf("failed to setup RTSP media: %w", err)
+	}
+
+	c.client.OnPacketRTPAny(c.onPacket)
+
+	if _, err := c.client.Play(nil); err != nil {
+		c.client.Close()
+		return fmt.Errorf("failed to start RTSP play: %w", err)
+	}
+
+	c.done = make(chan error, 1)
+	client := c.client
+	go func() {
+		c.done <- client.Wait()
+	}()
+
+	log.Printf("📡 gortsplib capture started: %s", maskPassword(rtspURL))
+	return nil
+}
+
+// Done satisfies RTSPClient, firing once the RTSP session ends - whether
+// from Stop's Close() or the camera dropping the connection on its own.
+func (c *GolibrtspClient) Done() <-chan error {
+	return c.done
+}
+
+// onPacket fans an RTP packet out to every subscriber as a packets.Packet.
+func (c *GolibrtspClient) onPacket(medi *description.Media, forma format.Format, pkt *rtp.Packet) {
+	p := packets.Packet{
+		Timestamp:  time.Now(),
+		IsKeyframe: isH264Keyframe(forma, pkt.Payload),
+		Codec:      forma.Codec(),
+		Data:       pkt.Payload,
+	}
+
+	c.timeline.Add(p)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range c.subs {
+		select {
+		case ch <- p:
+		default:
+			// Slow subscriber: drop rather than block the RTSP read loop.
+		}
+	}
+}
+
+// isH264Keyframe reports whether an RTP payload carries an H.264 IDR
+// (keyframe) NAL unit.
+func isH264Keyframe(forma format.Format, payload []byte) bool {
+	if _, ok := forma.(*format.H264); !ok || len(payload) == 0 {
+		return false
+	}
+	return payload[0]&0x1F == 5
+}
+
+// Subscribe satisfies RTSPClient, registering a new listener for the
+// packets read off the RTSP session.
+func (c *GolibrtspClient) Subscribe() (<-chan packets.Packet, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextSubID
+	c.nextSubID++
+	ch := make(chan packets.Packet, 64)
+	c.subs[id] = ch
+
+	return ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if ch, ok := c.subs[id]; ok {
+			delete(c.subs, id)
+			close(ch)
+		}
+	}
+}
+
+// Describe satisfies RTSPClient with the streams negotiated from the
+// camera's SDP.
+func (c *GolibrtspClient) Describe() []packets.Stream {
+	return c.streams
+}
+
+// Stop closes the RTSP session and every subscriber channel.
+func (c *GolibrtspClient) Stop() error {
+	if c.client != nil {
+		c.client.Close()
+	}
+
+	c.mu.Lock()
+	for _, ch := range c.subs {
+		close(ch)
+	}
+	c.subs = make(map[int]chan packets.Packet)
+	c.mu.Unlock()
+
+	log.Println("📡 gortsplib capture stopped")
+	return nil
+}
+
+// RecordEventClip snapshots preSeconds of buffered pre-roll plus
+// postSeconds of newly-arriving packets around a detection, and hands the
+// result to recorder.WriteClip so it's saved as an event clip recording
+// linked to eventID. It blocks until postSeconds has elapsed.
+func (c *GolibrtspClient) RecordEventClip(cameraID, eventID int, outputPath string, preSeconds, postSeconds time.Duration) (*storage.Recording, error) {
+	streams := c.Describe()
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("gortsplib: no stream described yet, cannot record event clip")
+	}
+
+	pkts := c.timeline.Snapshot(preSeconds, postSeconds)
+	return recorder.WriteClip(c.storageService, cameraID, eventID, outputPath, pkts, streams[0])
+}
+
+// streamsFromSession converts a negotiated SDP session into the
+// backend-agnostic packets.Stream metadata.
+func streamsFromSession(session *description.Session) []packets.Stream {
+	streams := make([]packets.Stream, 0, len(session.Medias))
+	for _, medi := range session.Medias {
+		for _, forma := range medi.Formats {
+			streams = append(streams, packets.Stream{
+				Codec:     forma.Codec(),
+				Timescale: uint32(forma.ClockRate()),
+			})
+		}
+	}
+	return streams
+}
+
+func transportPtr(t gortsplib.Transport) *gortsplib.Transport {
+	return &t
+}
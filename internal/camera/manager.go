@@ -0,0 +1,439 @@
+package camera
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/storage"
+)
+
+// reconcileInterval is how often Manager re-reads the cameras table to
+// pick up rows added or removed outside the process (e.g. through an
+// admin API), without needing a restart.
+const reconcileInterval = 30 * time.Second
+
+// reconnectInitialBackoff/reconnectMaxBackoff bound the delay between
+// Start retries when a camera fails to connect: it doubles from the
+// initial value up to the max on each consecutive failure.
+const (
+	reconnectInitialBackoff = 1 * time.Second
+	reconnectMaxBackoff     = 30 * time.Second
+)
+
+// CameraStatus is a point-in-time snapshot of one managed camera.
+type CameraStatus struct {
+	CameraID  int
+	Name      string
+	Running   bool
+	LastError string
+}
+
+// managedCamera is one camera's capture goroutine: its own client and
+// output directory, independent of every other camera Manager owns.
+type managedCamera struct {
+	camera storage.Camera
+
+	mu        sync.Mutex
+	client    RTSPClient
+	running   bool
+	lastError string
+
+	// cancel stops this camera's runCamera goroutine, including any
+	// pending reconnect backoff, when the camera is stopped or removed.
+	cancel context.CancelFunc
+}
+
+// Manager owns one capture client per row in the cameras table, each
+// running in its own goroutine with its own output subdirectory
+// (OutputDir/cam_<id>/), so a single process can capture from every
+// camera instead of just cfg.RTSPURL/cfg.Username/cfg.OutputDir.
+type Manager struct {
+	cfg     *config.Config
+	storage *storage.Service
+
+	mu      sync.Mutex
+	cameras map[int]*managedCamera
+
+	stopReconcile context.CancelFunc
+}
+
+// NewManager creates a Manager, starts capturing every camera currently in
+// the cameras table, and begins a background loop that picks up cameras
+// added or removed afterward.
+func NewManager(cfg *config.Config, storageService *storage.Service) (*Manager, error) {
+	m := &Manager{
+		cfg:     cfg,
+		storage: storageService,
+		cameras: make(map[int]*managedCamera),
+	}
+
+	cams, err := storageService.ListAllCameras()
+	if err != nil {
+		return nil, fmt.Errorf("camera manager: failed to list cameras: %w", err)
+	}
+
+	m.mu.Lock()
+	for _, cam := range cams {
+		m.startCameraLocked(cam)
+	}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopReconcile = cancel
+	go m.reconcileLoop(ctx)
+
+	return m, nil
+}
+
+// startCameraLocked starts a supervised capture goroutine for cam. Callers
+// must hold m.mu.
+func (m *Manager) startCameraLocked(cam storage.Camera) {
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &managedCamera{camera: cam, cancel: cancel}
+	m.cameras[cam.ID] = entry
+
+	go m.runCamera(ctx, entry)
+}
+
+// configUpdater is implemented by capture clients that can take a new
+// configuration snapshot without restarting their underlying connection
+// (currently just FFmpegClient; the gortsplib backend doesn't need it).
+// It's a separate optional interface rather than a method on RTSPClient
+// so backends that can't support live reconfiguration aren't forced to.
+type configUpdater interface {
+	UpdateConfig(cfg *config.Config)
+}
+
+// Reconfigure replaces the base Config every managed camera's per-camera
+// config is derived from, and pushes the new derived config to any
+// running client that implements configUpdater. It's meant to be called
+// from a config.Config.Watch callback when config.yaml changes; it does
+// not restart any camera's connection.
+func (m *Manager) Reconfigure(cfg *config.Config) {
+	m.mu.Lock()
+	m.cfg = cfg
+	entries := make([]*managedCamera, 0, len(m.cameras))
+	for _, entry := range m.cameras {
+		entries = append(entries, entry)
+	}
+	m.mu.Unlock()
+
+	for _, entry := range entries {
+		camCfg := m.cameraConfigFor(entry.camera)
+
+		entry.mu.Lock()
+		client := entry.client
+		entry.mu.Unlock()
+
+		if updater, ok := client.(configUpdater); ok {
+			updater.UpdateConfig(camCfg)
+		}
+	}
+}
+
+// cameraConfigFor derives cam's per-camera Config from the Manager's
+// current base config, the same way newClientForCamera does, without
+// touching the filesystem (callers already have an output directory).
+func (m *Manager) cameraConfigFor(cam storage.Camera) *config.Config {
+	camCfg := *m.cfg
+	camCfg.RTSPURL = cam.RTSPURL
+	camCfg.Username = cam.Username
+	camCfg.Password = cam.Password
+	camCfg.CameraID = cam.ID
+	camCfg.OutputDir = filepath.Join(m.cfg.OutputDir, fmt.Sprintf("cam_%d", cam.ID))
+
+	// Pull FrameRate/DetectionProfile overrides from the config.yaml
+	// cameras entry matching this camera by name, if any; the cameras
+	// table itself has no columns for either.
+	for _, entry := range m.cfg.Cameras {
+		if entry.Name != cam.Name {
+			continue
+		}
+		if entry.FrameRate > 0 {
+			camCfg.FrameRate = entry.FrameRate
+		}
+		camCfg.DetectionProfile = entry.DetectionProfile
+		break
+	}
+
+	return &camCfg
+}
+
+// newClientForCamera builds a capture client for cam under its own output
+// subdirectory (OutputDir/cam_<id>/), with cfg.CameraID set so the client
+// attributes events it creates to this camera rather than the storage
+// service's single default camera.
+func (m *Manager) newClientForCamera(cam storage.Camera) (RTSPClient, error) {
+	camCfg := m.cameraConfigFor(cam)
+
+	if err := os.MkdirAll(camCfg.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create output dir: %w", err)
+	}
+
+	return NewCaptureClient(camCfg, m.storage)
+}
+
+// runCamera builds and starts a capture client for entry.camera, retrying
+// with exponential backoff on failure until ctx is cancelled (by
+// stopCameraLocked). A successful Start doesn't end the loop: runCamera
+// keeps watching the client's Done channel, and a connection that dies
+// later (network blip, camera reboot, dropped RTSP session) falls back
+// into the same backoff loop as a failed Start, instead of being left
+// running forever. Every status change - error, reconnect, success - is
+// recorded both as the camera's status row and as an event so it shows up
+// in the events feed.
+func (m *Manager) runCamera(ctx context.Context, entry *managedCamera) {
+	backoff := reconnectInitialBackoff
+
+	for {
+		client, err := m.newClientForCamera(entry.camera)
+		if err == nil {
+			err = client.Start()
+		}
+
+		if err == nil {
+			entry.mu.Lock()
+			entry.client = client
+			entry.running = true
+			entry.lastError = ""
+			entry.mu.Unlock()
+
+			if statusErr := m.storage.UpdateCameraStatus(entry.camera.ID, storage.CameraStatusActive); statusErr != nil {
+				log.Printf("Warning: could not update camera %d status: %v", entry.camera.ID, statusErr)
+			}
+			m.recordStatusEvent(entry.camera, storage.EventTypeCameraOnline, storage.SeverityLow, "camera connected")
+			backoff = reconnectInitialBackoff
+
+			select {
+			case <-ctx.Done():
+				return
+			case err = <-client.Done():
+				// The connection died after a successful start; fall
+				// through to the same failure handling a failed Start
+				// gets below, so the backoff loop covers this path too.
+			}
+
+			entry.mu.Lock()
+			entry.running = false
+			entry.mu.Unlock()
+
+			if err == nil {
+				err = fmt.Errorf("camera connection closed")
+			}
+		}
+
+		entry.mu.Lock()
+		entry.lastError = err.Error()
+		entry.mu.Unlock()
+
+		log.Printf("⚠️  Camera manager: camera %d capture stopped: %v (retrying in %s)", entry.camera.ID, err, backoff)
+		if statusErr := m.storage.UpdateCameraStatus(entry.camera.ID, storage.CameraStatusError); statusErr != nil {
+			log.Printf("Warning: could not update camera %d status: %v", entry.camera.ID, statusErr)
+		}
+		m.recordStatusEvent(entry.camera, storage.EventTypeCameraOffline, storage.SeverityMedium, err.Error())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// recordStatusEvent writes a camera_online/camera_offline event for cam,
+// so status transitions surface in the events feed alongside detections.
+func (m *Manager) recordStatusEvent(cam storage.Camera, eventType, severity, detail string) {
+	title := "Camera Online"
+	if eventType == storage.EventTypeCameraOffline {
+		title = "Camera Offline"
+	}
+	message := fmt.Sprintf("Camera %d (%s): %s", cam.ID, cam.Name, detail)
+
+	if err := m.storage.CreateEventForCamera(cam.ID, eventType, severity, title, message); err != nil {
+		log.Printf("Warning: could not record status event for camera %d: %v", cam.ID, err)
+	}
+}
+
+// stopCameraLocked stops entry's reconnect loop and client (if one is
+// currently running) and marks the camera inactive. Callers must hold m.mu.
+func (m *Manager) stopCameraLocked(entry *managedCamera) {
+	if entry.cancel != nil {
+		entry.cancel()
+	}
+
+	entry.mu.Lock()
+	client := entry.client
+	entry.running = false
+	entry.mu.Unlock()
+
+	if client != nil {
+		if err := client.Stop(); err != nil {
+			log.Printf("Warning: camera %d did not stop cleanly: %v", entry.camera.ID, err)
+		}
+	}
+
+	if err := m.storage.UpdateCameraStatus(entry.camera.ID, storage.CameraStatusInactive); err != nil {
+		log.Printf("Warning: could not update camera %d status: %v", entry.camera.ID, err)
+	}
+}
+
+// AddCamera persists a new camera row and starts capturing it immediately.
+func (m *Manager) AddCamera(cam *storage.Camera) error {
+	if err := m.storage.CreateCamera(cam); err != nil {
+		return fmt.Errorf("camera manager: failed to create camera: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.startCameraLocked(*cam)
+	return nil
+}
+
+// RemoveCamera stops capturing a camera. The camera's row and history are
+// left in place; only its capture goroutine is torn down.
+func (m *Manager) RemoveCamera(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.cameras[id]
+	if !ok {
+		return fmt.Errorf("camera manager: camera %d is not managed", id)
+	}
+
+	m.stopCameraLocked(entry)
+	delete(m.cameras, id)
+	return nil
+}
+
+// RestartCamera stops and recreates a camera's capture client, reloading
+// its row from storage first so changed credentials or a changed RTSP URL
+// take effect without a process restart.
+func (m *Manager) RestartCamera(id int) error {
+	m.mu.Lock()
+	entry, ok := m.cameras[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("camera manager: camera %d is not managed", id)
+	}
+
+	m.mu.Lock()
+	m.stopCameraLocked(entry)
+	m.mu.Unlock()
+
+	cam, err := m.storage.GetCamera(id)
+	if err != nil {
+		return fmt.Errorf("camera manager: failed to reload camera %d: %w", id, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.startCameraLocked(*cam)
+	return nil
+}
+
+// Status returns a snapshot of every camera this Manager is capturing.
+func (m *Manager) Status() map[int]CameraStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make(map[int]CameraStatus, len(m.cameras))
+	for id, entry := range m.cameras {
+		entry.mu.Lock()
+		statuses[id] = CameraStatus{
+			CameraID:  id,
+			Name:      entry.camera.Name,
+			Running:   entry.running,
+			LastError: entry.lastError,
+		}
+		entry.mu.Unlock()
+	}
+	return statuses
+}
+
+// Client returns the running capture client for a managed camera, e.g. for
+// wiring a broadcast.Manager to the same packet source.
+func (m *Manager) Client(id int) (RTSPClient, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.cameras[id]
+	if !ok {
+		return nil, false
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.client, entry.client != nil
+}
+
+// reconcileLoop periodically re-reads the cameras table and starts/stops
+// capture goroutines so the set of managed cameras matches it, without
+// requiring a process restart.
+func (m *Manager) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.reconcile()
+		}
+	}
+}
+
+func (m *Manager) reconcile() {
+	cams, err := m.storage.ListAllCameras()
+	if err != nil {
+		log.Printf("⚠️  Camera manager: could not reconcile cameras: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[int]bool, len(cams))
+	for _, cam := range cams {
+		seen[cam.ID] = true
+		if _, ok := m.cameras[cam.ID]; !ok {
+			log.Printf("📷 Camera manager: picked up new camera %d (%s)", cam.ID, cam.Name)
+			m.startCameraLocked(cam)
+		}
+	}
+
+	for id, entry := range m.cameras {
+		if !seen[id] {
+			log.Printf("📷 Camera manager: camera %d removed from cameras table, stopping capture", id)
+			m.stopCameraLocked(entry)
+			delete(m.cameras, id)
+		}
+	}
+}
+
+// Close stops every managed camera and the reconcile loop.
+func (m *Manager) Close() error {
+	if m.stopReconcile != nil {
+		m.stopReconcile()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, entry := range m.cameras {
+		m.stopCameraLocked(entry)
+		delete(m.cameras, id)
+	}
+	return nil
+}
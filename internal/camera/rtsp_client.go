@@ -0,0 +1,115 @@
+package camera
+
+import (
+	"fmt"
+	"time"
+
+	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/packets"
+	"camera-detection-project/internal/recorder"
+)
+
+// RTSPClient is implemented by every capture backend (FFmpegClient,
+// GolibrtspClient) so downstream code - the frame extractor, recorder, and
+// future broadcast/WebRTC muxers - can consume a camera's stream without
+// caring which backend produced it.
+type RTSPClient interface {
+	// Start begins capturing from the camera. It returns once capture has
+	// started; errors after that point surface as log output and events
+	// rather than a returned error, matching the existing FFmpegClient.
+	Start() error
+
+	// Stop halts capture and releases the backend's resources.
+	Stop() error
+
+	// Subscribe registers a new listener for packets produced by this
+	// client, mirroring eventbus.Broker.Subscribe: call the returned func
+	// to unsubscribe. Backends that don't produce in-process packets (e.g.
+	// FFmpegClient, which lets ffmpeg do its own muxing) return a channel
+	// that is simply never written to.
+	Subscribe() (<-chan packets.Packet, func())
+
+	// Describe returns the media streams negotiated with the camera, or
+	// nil if that isn't known yet. FFmpegClient never sees the SDP and
+	// always reports a single placeholder H.264 stream.
+	Describe() []packets.Stream
+
+	// Done returns a channel that receives exactly once when the
+	// underlying connection ends, whether from a deliberate Stop or an
+	// unexpected disconnect (dropped RTSP session, crashed ffmpeg
+	// process). camera.Manager selects on it after a successful Start so
+	// it notices a connection that died after connecting, not just one
+	// that never connected at all.
+	Done() <-chan error
+}
+
+// NewCaptureClient builds the RTSPClient backend selected by
+// cfg.Capture.Backend ("ffmpeg" or "gortsplib").
+func NewCaptureClient(cfg *config.Config, storage StorageService) (RTSPClient, error) {
+	switch cfg.Capture.Backend {
+	case "", "ffmpeg":
+		return NewFFmpegClientWithStorage(cfg, storage)
+	case "gortsplib":
+		return NewGolibrtspClient(cfg, storage)
+	default:
+		return nil, fmt.Errorf("unknown capture backend: %q", cfg.Capture.Backend)
+	}
+}
+
+// TestConnection opens a capture session against the backend selected by
+// cfg.Capture.Backend and immediately tears it down, proving the camera is
+// reachable without doing anything with the packets it produces.
+func TestConnection(cfg *config.Config) error {
+	client, err := NewCaptureClient(cfg, nil)
+	if err != nil {
+		return err
+	}
+	if err := client.Start(); err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	return client.Stop()
+}
+
+// RecordSegment captures duration of video from the camera and muxes it to
+// outputPath. It only supports the gortsplib backend: that's the only
+// capture backend that delivers real packets through Subscribe (FFmpegClient
+// leaves ffmpeg to do its own out-of-process muxing into its own segment
+// files, so there's nothing to collect here).
+func RecordSegment(cfg *config.Config, outputPath string, duration time.Duration) error {
+	return RecordAroundEvent(cfg, outputPath, 0, duration)
+}
+
+// RecordAroundEvent opens a fresh capture session, waits preRoll to let its
+// packets.Timeline buffer up, then snapshots preRoll of buffered packets plus
+// postRoll of newly-arriving ones and muxes the result to outputPath without
+// re-encoding. It's the standalone/CLI counterpart to
+// GolibrtspClient.RecordEventClip: that method snapshots from a capture
+// session a camera.Manager already keeps running (so its pre-roll buffer
+// reflects real history), while this one starts its own short-lived session
+// and is only as good as whatever arrives after preRoll elapses. Like
+// RecordSegment, it only supports the gortsplib backend.
+func RecordAroundEvent(cfg *config.Config, outputPath string, preRoll, postRoll time.Duration) error {
+	if cfg.Capture.Backend != "gortsplib" {
+		return fmt.Errorf("camera: RecordAroundEvent requires capture backend %q, got %q", "gortsplib", cfg.Capture.Backend)
+	}
+
+	client, err := NewGolibrtspClient(cfg, nil)
+	if err != nil {
+		return err
+	}
+	if err := client.Start(); err != nil {
+		return fmt.Errorf("failed to start capture: %w", err)
+	}
+	defer client.Stop()
+
+	time.Sleep(preRoll)
+
+	streams := client.Describe()
+	if len(streams) == 0 {
+		return fmt.Errorf("camera: no stream described yet, cannot record clip")
+	}
+
+	pkts := client.timeline.Snapshot(preRoll, postRoll)
+	_, _, err = recorder.MuxClip(outputPath, pkts, streams[0])
+	return err
+}
@@ -11,35 +11,19 @@ import (
 	"camera-detection-project/internal/config"
 )
 
-// TestRTSPConnection tests RTSP connection using ffprobe
-func TestRTSPConnection(rtspURL string) error {
-	log.Printf("Testing RTSP connection to: %s", maskPassword(rtspURL))
-
-	args := []string{
-		"-rtsp_transport", "tcp",
-		"-i", rtspURL,
-		"-t", "3",                // Test for 3 seconds
-		"-f", "null",             // No output
-		"-v", "quiet",            // Quiet output
-		"-",
-	}
-
-	cmd := exec.Command("ffprobe", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("RTSP connection test failed: %w", err)
-	}
-
-	log.Println("RTSP connection test successful")
-	return nil
-}
-
-// TestRTSPConnectionWithConfig tests connection using config
+// TestRTSPConnectionWithConfig tests connection using the capture backend
+// selected by cfg.Capture.Backend, sharing the same session-oriented path
+// that capture itself uses instead of a one-off ffprobe invocation.
 func TestRTSPConnectionWithConfig(cfg *config.Config) error {
-	rtspURL := buildRTSPURL(cfg.RTSPURL, cfg.Username, cfg.Password)
-	return TestRTSPConnection(rtspURL)
+	return TestConnection(cfg)
 }
 
-// ExtractSingleFrame extracts one frame from RTSP stream
+// ExtractSingleFrame extracts one frame from RTSP stream.
+//
+// This still shells out directly rather than going through RTSPClient: the
+// capture backends describe H.264 packets, and this repo has no in-process
+// H.264 decoder to turn those into a still image, so ffmpeg's own decode is
+// unavoidable here even for the gortsplib backend.
 func ExtractSingleFrame(rtspURL, outputPath string) error {
 	log.Printf("Extracting frame from: %s", maskPassword(rtspURL))
 
@@ -51,9 +35,9 @@ func ExtractSingleFrame(rtspURL, outputPath string) error {
 	args := []string{
 		"-rtsp_transport", "tcp",
 		"-i", rtspURL,
-		"-vframes", "1",           // Extract only 1 frame
-		"-q:v", "2",              // High quality
-		"-y",                     // Overwrite output file
+		"-vframes", "1", // Extract only 1 frame
+		"-q:v", "2", // High quality
+		"-y", // Overwrite output file
 		outputPath,
 	}
 
@@ -88,8 +72,8 @@ func GenerateThumbnail(imagePath, outputPath string, size string) error {
 	args := []string{
 		"-i", imagePath,
 		"-vf", fmt.Sprintf("scale=%s", size),
-		"-q:v", "3",              // Good quality for thumbnail
-		"-y",                     // Overwrite output file
+		"-q:v", "3", // Good quality for thumbnail
+		"-y", // Overwrite output file
 		outputPath,
 	}
 
@@ -102,34 +86,6 @@ func GenerateThumbnail(imagePath, outputPath string, size string) error {
 	return nil
 }
 
-// RecordVideoSegment records a short video segment
-func RecordVideoSegment(rtspURL string, outputPath string, duration int) error {
-	log.Printf("Recording %d second segment from: %s", duration, maskPassword(rtspURL))
-
-	// Ensure output directory exists
-	if err := createOutputDir(outputPath); err != nil {
-		return err
-	}
-
-	args := []string{
-		"-rtsp_transport", "tcp",
-		"-i", rtspURL,
-		"-t", fmt.Sprintf("%d", duration), // Record for N seconds
-		"-c:v", "libx264",
-		"-preset", "fast",
-		"-y",                              // Overwrite output file
-		outputPath,
-	}
-
-	cmd := exec.Command("ffmpeg", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to record segment: %w", err)
-	}
-
-	log.Printf("Video segment recorded: %s", outputPath)
-	return nil
-}
-
 // GetVideoInfo extracts basic information about a video file
 func GetVideoInfo(videoPath string) (*VideoInfo, error) {
 	args := []string{
@@ -216,7 +172,7 @@ func QuickCameraTest(cfg *config.Config) error {
 	// 2. Extract test frame
 	timestamp := time.Now().Format("20060102_150405")
 	framePath := filepath.Join(cfg.OutputDir, fmt.Sprintf("test_frame_%s.jpg", timestamp))
-	
+
 	if err := ExtractFrameWithConfig(cfg, framePath); err != nil {
 		return fmt.Errorf("frame extraction failed: %w", err)
 	}
@@ -235,4 +191,4 @@ func QuickCameraTest(cfg *config.Config) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
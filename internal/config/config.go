@@ -1,34 +1,125 @@
 package config
 
 import (
-	"log"
 	"bufio"
 	"os"
-	"strconv"
 	"strings"
 	"time"
+
+	"go.uber.org/zap"
+
+	"camera-detection-project/internal/logging"
 )
 
+// pkgLogger backs the handful of log lines this package emits while
+// loading configuration, before a Config (and therefore a storage.Service
+// with its own configured Logger) exists yet. It reads LOG_LEVEL/LOG_FORMAT
+// directly from the environment rather than from Config, since .env hasn't
+// necessarily been applied to the environment when these log lines fire.
+var pkgLogger = newBootstrapLogger()
+
+func newBootstrapLogger() *zap.Logger {
+	logger, err := logging.New(getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", "console"))
+	if err != nil {
+		return zap.NewNop()
+	}
+	return logger
+}
+
 type DetectionServiceConfig struct {
-    URL               string
-    Timeout           time.Duration
-    MaxRetries        int
-    ConfidenceThreshold float64
+	URL                 string
+	Timeout             time.Duration
+	MaxRetries          int
+	ConfidenceThreshold float64
+	// SupportsBatch enables coalescing concurrent detection requests into a
+	// single POST to URL+"/detect/batch" instead of one POST per frame. Only
+	// set this for a detection service that actually exposes that endpoint.
+	SupportsBatch bool
+}
+
+// CaptureConfig selects and configures the camera.RTSPClient backend.
+type CaptureConfig struct {
+	// Backend is "ffmpeg" (default, shells out to ffmpeg) or "gortsplib"
+	// (native RTSP/RTP client, no ffmpeg dependency).
+	Backend string
+}
+
+// CameraConfig describes one camera for storage.Service's syncCameras to
+// upsert into the cameras table at startup.
+type CameraConfig struct {
+	// Name is the stable key syncCameras upserts by. Renaming a camera
+	// here makes syncCameras treat it as a brand new one rather than
+	// updating the existing row.
+	Name     string
+	RTSPURL  string
+	Username string
+	Password string
+	// FrameRate overrides the top-level FrameRate for just this camera;
+	// zero means "use the top-level FrameRate".
+	FrameRate int
+	// DetectionProfile names a detection tuning preset (e.g. "outdoor",
+	// "low-light", "entrance") this camera should use. Interpreting it is
+	// left to the capture backend; an empty value means "use whatever the
+	// detection service's default is".
+	DetectionProfile string
 }
 
 type Config struct {
 	// Camera settings
-	RTSPURL         string
-	Username        string
-	Password        string
-	CameraTimeout   time.Duration
-	FrameRate       int
-	SaveFrames      bool
-	OutputDir       string
-	FFmpegPath      string
+	RTSPURL          string
+	Username         string
+	Password         string
+	CameraTimeout    time.Duration
+	FrameRate        int
+	SaveFrames       bool
+	OutputDir        string
+	FFmpegPath       string
 	DetectionEnabled bool
-	
+	// DetectionProfile names a detection tuning preset this camera should
+	// use (see CameraConfig.DetectionProfile); empty means "use whatever
+	// the detection service's default is". It's set per-camera by
+	// camera.Manager from the matching config.yaml cameras entry.
+	DetectionProfile string
+	// FrameDedupHamming is the maximum pHash Hamming distance (out of 64
+	// bits) to the previous stored frame for the same camera at which
+	// SaveFrame rejects an incoming frame as a near-duplicate of it.
+	// Lower is stricter; 0 only rejects exact pHash matches.
+	FrameDedupHamming int
+	// CameraID identifies which cameras-table row this Config was derived
+	// for, so a capture client reports recordings/frames/events against
+	// the right camera. It's set by camera.Manager when it builds a
+	// per-camera Config copy; it's meaningless (and never read) for a
+	// client built without a storage.Service, e.g. the test-camera CLI.
+	CameraID int
+	// PreEventSeconds/PostEventSeconds bound the clip a packets.Timeline
+	// snapshots around a detection: how much buffered video comes before
+	// the trigger, and how much newly-arriving video comes after it.
+	PreEventSeconds  time.Duration
+	PostEventSeconds time.Duration
+
+	// Cameras configures an explicit list of cameras, so one process can
+	// run as an NVR capturing several RTSP sources instead of just the
+	// single implicit camera described by RTSPURL/Username/Password
+	// above. Left empty, storage.Service's syncCameras falls back to that
+	// single top-level camera, exactly as before this field existed.
+	Cameras []CameraConfig
+
+	// API server settings
+	APIPort int
+	// AuthSecret signs session tokens issued on login/register; set it to a
+	// long random value in production.
+	AuthSecret string
+
+	// Metrics settings
+	MetricsSampleInterval  time.Duration
+	DiskFreeThresholdBytes int64
+
 	// Database settings
+	// DatabaseURL selects both the driver and the storage.Dialect via its
+	// scheme: "postgres://..." or "sqlite:///path/to/file.db". When unset,
+	// it is built from the discrete fields below so existing Postgres
+	// deployments keep working unchanged.
+	DatabaseURL      string
 	DatabaseHost     string
 	DatabasePort     int
 	DatabaseUser     string
@@ -38,39 +129,64 @@ type Config struct {
 
 	// Detection settings
 	DetectionService DetectionServiceConfig
-}
 
-func Load() (*Config, error) {
-	// Try to load .env file if it exists
-	loadEnvFile()
-
-	cfg := &Config{
-		// Camera configuration
-		RTSPURL:          getEnv("RTSP_URL", "rtsp://192.168.1.100:554/stream1"),
-		Username:         getEnv("CAMERA_USERNAME", "admin"),
-		Password:         getEnv("CAMERA_PASSWORD", ""),
-		CameraTimeout:    getDurationEnv("CAMERA_TIMEOUT", 30*time.Second),
-		FrameRate:        getIntEnv("FRAME_RATE", 5),
-		SaveFrames:       getBoolEnv("SAVE_FRAMES", true),
-		OutputDir:        getEnv("OUTPUT_DIR", "./output"),
-		FFmpegPath:       getEnv("FFMPEG_PATH", "ffmpeg"),
-		DetectionEnabled: getBoolEnv("DETECTION_ENABLED", true),
-
-		// Database configuration
-		DatabaseHost:     getEnv("DATABASE_HOST", "localhost"),
-		DatabasePort:     getIntEnv("DATABASE_PORT", 5432),
-		DatabaseUser:     getEnv("DATABASE_USER", "postgres"),
-		DatabasePassword: getEnv("DATABASE_PASSWORD", "postgres"),
-		DatabaseName:     getEnv("DATABASE_NAME", "surveillance"),
-		DatabaseSSLMode:  getEnv("DATABASE_SSL_MODE", "disable"),
-	}
-
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		return nil, err
-	}
-
-	return cfg, nil
+	// Capture settings
+	Capture CaptureConfig
+
+	// Logging settings
+	// LogLevel is "debug", "info", "warn", or "error". LogFormat is "json"
+	// (for shipping to Loki/ELK) or "console" (human-readable, the
+	// default for local development).
+	LogLevel  string
+	LogFormat string
+
+	// Storage backend settings
+	// StorageBackend selects where storage.Service writes finished frame/
+	// recording blobs: "local" (default, under OutputDir), "s3", "webdav",
+	// or "ssh"/"sftp". Only the fields for the selected backend need to be
+	// set.
+	StorageBackend string
+
+	S3Endpoint  string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+
+	WebDAVURL      string
+	WebDAVUsername string
+	WebDAVPassword string
+
+	SSHHost      string
+	SSHPort      int
+	SSHUser      string
+	SSHPassword  string
+	SSHKeyPath   string
+	SSHRemoteDir string
+
+	// Retention settings
+	// RetentionMaxAgeDays deletes recordings (and their frames) older than
+	// this many days; 0 disables age-based cleanup.
+	RetentionMaxAgeDays int
+	// RetentionMaxSizeGB deletes the oldest recordings, regardless of age,
+	// until total storage usage is back under this cap; 0 disables the
+	// size-based cleanup.
+	RetentionMaxSizeGB int64
+	// RetentionKeepEventsDays is a grace period: a recording linked to an
+	// event at or above RetentionMinSeverity is protected from both sweeps
+	// until that many days after the event fired.
+	RetentionKeepEventsDays int
+	// RetentionMinSeverity is the lowest event severity ("low", "medium",
+	// "high", "critical") whose recordings earn the KeepEventsDays grace
+	// period.
+	RetentionMinSeverity string
+
+	// configFilePath and loadOpts remember how this Config was built so
+	// Watch can rerun the exact same layering (yaml file -> .env ->
+	// environment -> CLI overrides) on a config.yaml change instead of
+	// guessing at the original inputs.
+	configFilePath string
+	loadOpts       []Option
 }
 
 // loadEnvFile loads .env file if it exists
@@ -103,7 +219,7 @@ func loadEnvFile() {
 				if strings.Contains(strings.ToLower(key), "password") {
 					displayValue = maskValue(value)
 				}
-				log.Printf("📝 Загружен %s = %s", key, displayValue)
+				pkgLogger.Debug("loaded env var from .env", zap.String("key", key), zap.String("value", displayValue))
 			}
 		}
 	}
@@ -116,33 +232,6 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
-		}
-	}
-	return defaultValue
-}
-
-func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value + "s"); err == nil {
-			return duration
-		}
-	}
-	return defaultValue
-}
-
 func maskValue(value string) string {
 	if value == "" {
 		return "(пустое)"
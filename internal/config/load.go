@@ -0,0 +1,373 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is where Load looks for an optional YAML overlay
+// unless CONFIG_FILE points somewhere else. It's intentionally the same
+// default filename Watch re-reads on a write event.
+const defaultConfigFile = "config.yaml"
+
+// ValidationError collects every problem Load finds with the merged
+// configuration, so a misconfigured deployment sees all of them in one
+// failure instead of fixing one value at a time.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("config: %d validation error(s): %s", len(e.Errors), strings.Join(e.Errors, "; "))
+}
+
+// Option customizes a Load call. The only kind right now is WithCLI,
+// applied last so command-line flags win over every other source.
+type Option func(*Config)
+
+// WithCLI applies o on top of every other configured layer. Nil/zero
+// fields in o are left alone, so a flag the caller didn't set doesn't
+// clobber config.yaml/.env/the environment.
+func WithCLI(o CLIOverrides) Option {
+	return func(cfg *Config) {
+		if o.FrameRate != nil {
+			cfg.FrameRate = *o.FrameRate
+		}
+		if o.ConfidenceThreshold != nil {
+			cfg.DetectionService.ConfidenceThreshold = *o.ConfidenceThreshold
+		}
+		if o.APIPort != nil {
+			cfg.APIPort = *o.APIPort
+		}
+		if o.RTSPURL != nil {
+			cfg.RTSPURL = *o.RTSPURL
+		}
+	}
+}
+
+// CLIOverrides holds the command-line flag values Load should apply on
+// top of config.yaml/.env/the environment. A nil field means "the flag
+// wasn't passed"; main.go builds this from flag.Int/flag.Float64/etc.
+// pointers after flag.Parse().
+type CLIOverrides struct {
+	FrameRate           *int
+	ConfidenceThreshold *float64
+	APIPort             *int
+	RTSPURL             *string
+}
+
+// Load builds a Config by merging, in increasing order of precedence:
+// built-in defaults, an optional YAML file (CONFIG_FILE, default
+// "config.yaml"), a .env file, the process environment, and finally any
+// CLI overrides passed in opts. It validates the merged result and
+// returns a *ValidationError listing every problem found, rather than
+// failing on the first one.
+func Load(opts ...Option) (*Config, error) {
+	cfg := defaultConfig()
+
+	configFilePath := getEnv("CONFIG_FILE", defaultConfigFile)
+	var errs []string
+
+	if err := applyYAMLFile(cfg, configFilePath); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	loadEnvFile()
+	errs = append(errs, applyEnv(cfg)...)
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	errs = append(errs, validate(cfg)...)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("config: failed to create output directory %q: %w", cfg.OutputDir, err)
+	}
+
+	cfg.configFilePath = configFilePath
+	cfg.loadOpts = opts
+	return cfg, nil
+}
+
+// defaultConfig returns the configuration this service ran with before
+// config.yaml/.env/environment/CLI layering existed, as a starting point
+// for Load to overlay onto.
+func defaultConfig() *Config {
+	return &Config{
+		RTSPURL:          "rtsp://192.168.1.100:554/stream1",
+		Username:         "admin",
+		Password:         "",
+		CameraTimeout:    30 * time.Second,
+		FrameRate:        5,
+		SaveFrames:       true,
+		OutputDir:        "./output",
+		FFmpegPath:       "ffmpeg",
+		DetectionEnabled: true,
+		PreEventSeconds:  10 * time.Second,
+		PostEventSeconds: 15 * time.Second,
+
+		APIPort:    8081,
+		AuthSecret: "dev-secret-change-me",
+
+		MetricsSampleInterval:  15 * time.Second,
+		DiskFreeThresholdBytes: 1 << 30, // 1 GiB
+
+		DatabaseURL:      "",
+		DatabaseHost:     "localhost",
+		DatabasePort:     5432,
+		DatabaseUser:     "postgres",
+		DatabasePassword: "postgres",
+		DatabaseName:     "surveillance",
+		DatabaseSSLMode:  "disable",
+
+		DetectionService: DetectionServiceConfig{
+			Timeout:             10 * time.Second,
+			MaxRetries:          3,
+			ConfidenceThreshold: 0.5,
+		},
+
+		Capture: CaptureConfig{
+			Backend: "ffmpeg",
+		},
+
+		LogLevel:  "info",
+		LogFormat: "console",
+
+		StorageBackend: "local",
+		S3UseSSL:       true,
+
+		SSHPort: 22,
+
+		RetentionMaxAgeDays:     30,
+		RetentionMaxSizeGB:      0,
+		RetentionKeepEventsDays: 90,
+		RetentionMinSeverity:    "high",
+
+		FrameDedupHamming: 5,
+	}
+}
+
+// applyEnv overlays every non-empty environment variable onto cfg,
+// mirroring the variable names the pre-layered Load accepted, and
+// returns one message per variable whose value couldn't be parsed.
+// Unlike config.yaml, an environment variable is either unset (leave the
+// lower layer's value alone) or set (override it) — there's no tri-state
+// for a value like "explicitly false" the way yaml pointers give us.
+func applyEnv(cfg *Config) []string {
+	var errs []string
+
+	str := func(key string, dst *string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	intVal := func(key string, dst *int) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid integer %q", key, v))
+			return
+		}
+		*dst = n
+	}
+	int64Val := func(key string, dst *int64) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid integer %q", key, v))
+			return
+		}
+		*dst = n
+	}
+	floatVal := func(key string, dst *float64) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid number %q", key, v))
+			return
+		}
+		*dst = f
+	}
+	boolVal := func(key string, dst *bool) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid boolean %q", key, v))
+			return
+		}
+		*dst = b
+	}
+	durationVal := func(key string, dst *time.Duration) {
+		v := os.Getenv(key)
+		if v == "" {
+			return
+		}
+		// Accept a real Go duration ("30s", "1h30m") as well as a bare
+		// number of seconds, for deployments still setting e.g.
+		// CAMERA_TIMEOUT=30. Previously this always appended "s" before
+		// parsing, which silently broke CAMERA_TIMEOUT=30s (became "30ss").
+		if d, err := time.ParseDuration(v); err == nil {
+			*dst = d
+			return
+		}
+		if secs, err := strconv.Atoi(v); err == nil {
+			*dst = time.Duration(secs) * time.Second
+			return
+		}
+		errs = append(errs, fmt.Sprintf("%s: invalid duration %q (want a Go duration like \"30s\" or a bare number of seconds)", key, v))
+	}
+
+	str("RTSP_URL", &cfg.RTSPURL)
+	str("CAMERA_USERNAME", &cfg.Username)
+	str("CAMERA_PASSWORD", &cfg.Password)
+	durationVal("CAMERA_TIMEOUT", &cfg.CameraTimeout)
+	intVal("FRAME_RATE", &cfg.FrameRate)
+	boolVal("SAVE_FRAMES", &cfg.SaveFrames)
+	str("OUTPUT_DIR", &cfg.OutputDir)
+	str("FFMPEG_PATH", &cfg.FFmpegPath)
+	boolVal("DETECTION_ENABLED", &cfg.DetectionEnabled)
+	durationVal("PRE_EVENT_SECONDS", &cfg.PreEventSeconds)
+	durationVal("POST_EVENT_SECONDS", &cfg.PostEventSeconds)
+
+	intVal("API_PORT", &cfg.APIPort)
+	str("AUTH_SECRET", &cfg.AuthSecret)
+
+	durationVal("METRICS_SAMPLE_INTERVAL", &cfg.MetricsSampleInterval)
+	int64Val("DISK_FREE_THRESHOLD_BYTES", &cfg.DiskFreeThresholdBytes)
+
+	str("DATABASE_URL", &cfg.DatabaseURL)
+	str("DATABASE_HOST", &cfg.DatabaseHost)
+	intVal("DATABASE_PORT", &cfg.DatabasePort)
+	str("DATABASE_USER", &cfg.DatabaseUser)
+	str("DATABASE_PASSWORD", &cfg.DatabasePassword)
+	str("DATABASE_NAME", &cfg.DatabaseName)
+	str("DATABASE_SSL_MODE", &cfg.DatabaseSSLMode)
+
+	str("DETECTION_SERVICE_URL", &cfg.DetectionService.URL)
+	durationVal("DETECTION_SERVICE_TIMEOUT", &cfg.DetectionService.Timeout)
+	intVal("DETECTION_SERVICE_MAX_RETRIES", &cfg.DetectionService.MaxRetries)
+	floatVal("DETECTION_CONFIDENCE_THRESHOLD", &cfg.DetectionService.ConfidenceThreshold)
+	boolVal("DETECTION_SERVICE_SUPPORTS_BATCH", &cfg.DetectionService.SupportsBatch)
+
+	str("CAPTURE_BACKEND", &cfg.Capture.Backend)
+
+	str("LOG_LEVEL", &cfg.LogLevel)
+	str("LOG_FORMAT", &cfg.LogFormat)
+
+	str("STORAGE_BACKEND", &cfg.StorageBackend)
+
+	str("S3_ENDPOINT", &cfg.S3Endpoint)
+	str("S3_BUCKET", &cfg.S3Bucket)
+	str("S3_ACCESS_KEY", &cfg.S3AccessKey)
+	str("S3_SECRET_KEY", &cfg.S3SecretKey)
+	boolVal("S3_USE_SSL", &cfg.S3UseSSL)
+
+	str("WEBDAV_URL", &cfg.WebDAVURL)
+	str("WEBDAV_USERNAME", &cfg.WebDAVUsername)
+	str("WEBDAV_PASSWORD", &cfg.WebDAVPassword)
+
+	str("SSH_HOST", &cfg.SSHHost)
+	intVal("SSH_PORT", &cfg.SSHPort)
+	str("SSH_USER", &cfg.SSHUser)
+	str("SSH_PASSWORD", &cfg.SSHPassword)
+	str("SSH_KEY_PATH", &cfg.SSHKeyPath)
+	str("SSH_REMOTE_DIR", &cfg.SSHRemoteDir)
+
+	intVal("RETENTION_MAX_AGE_DAYS", &cfg.RetentionMaxAgeDays)
+	int64Val("RETENTION_MAX_SIZE_GB", &cfg.RetentionMaxSizeGB)
+	intVal("RETENTION_KEEP_EVENTS_DAYS", &cfg.RetentionKeepEventsDays)
+	str("RETENTION_MIN_SEVERITY", &cfg.RetentionMinSeverity)
+
+	intVal("FRAME_DEDUP_HAMMING", &cfg.FrameDedupHamming)
+
+	return errs
+}
+
+// applyYAMLFile overlays path's contents onto cfg if it exists. A
+// missing file is not an error — config.yaml is optional, since
+// everything it can set is also settable via .env/the environment.
+func applyYAMLFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("config: failed to read %q: %w", path, err)
+	}
+
+	var overlay yamlConfig
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return fmt.Errorf("config: failed to parse %q: %w", path, err)
+	}
+
+	overlay.applyTo(cfg)
+	return nil
+}
+
+// validate checks the fields most likely to produce a confusing failure
+// deep in another package (an empty RTSP URL, an out-of-range port or
+// confidence threshold) and returns one message per problem found.
+func validate(cfg *Config) []string {
+	var errs []string
+
+	if strings.TrimSpace(cfg.RTSPURL) == "" {
+		errs = append(errs, "RTSP_URL must not be empty")
+	}
+	if cfg.APIPort < 1 || cfg.APIPort > 65535 {
+		errs = append(errs, fmt.Sprintf("API_PORT must be between 1 and 65535, got %d", cfg.APIPort))
+	}
+	if cfg.DetectionService.ConfidenceThreshold < 0 || cfg.DetectionService.ConfidenceThreshold > 1 {
+		errs = append(errs, fmt.Sprintf("DETECTION_CONFIDENCE_THRESHOLD must be between 0 and 1, got %v", cfg.DetectionService.ConfidenceThreshold))
+	}
+	if cfg.FrameRate <= 0 {
+		errs = append(errs, fmt.Sprintf("FRAME_RATE must be positive, got %d", cfg.FrameRate))
+	}
+	if cfg.FrameDedupHamming < 0 || cfg.FrameDedupHamming > 64 {
+		errs = append(errs, fmt.Sprintf("FRAME_DEDUP_HAMMING must be between 0 and 64, got %d", cfg.FrameDedupHamming))
+	}
+
+	switch cfg.StorageBackend {
+	case "local", "s3", "webdav", "ssh", "sftp":
+	default:
+		errs = append(errs, fmt.Sprintf("STORAGE_BACKEND must be one of local, s3, webdav, ssh, got %q", cfg.StorageBackend))
+	}
+
+	seenNames := make(map[string]bool, len(cfg.Cameras))
+	for _, cam := range cfg.Cameras {
+		if strings.TrimSpace(cam.Name) == "" {
+			errs = append(errs, "every entry in cameras must set a name")
+			continue
+		}
+		if seenNames[cam.Name] {
+			errs = append(errs, fmt.Sprintf("cameras entry %q is defined more than once", cam.Name))
+		}
+		seenNames[cam.Name] = true
+
+		if strings.TrimSpace(cam.RTSPURL) == "" {
+			errs = append(errs, fmt.Sprintf("cameras entry %q must set rtsp_url", cam.Name))
+		}
+	}
+
+	return errs
+}
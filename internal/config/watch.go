@@ -0,0 +1,70 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// Watch watches c's config.yaml file for changes and calls onChange with a
+// freshly reloaded Config each time it's written, using the exact same
+// layers (config.yaml -> .env -> environment -> CLI overrides) c itself was
+// built with. A reload that fails to parse or validate is logged and
+// skipped, keeping the last good configuration in place; onChange is only
+// called with a Config that passed validate. The watch goroutine runs until
+// ctx is cancelled.
+func (c *Config) Watch(ctx context.Context, onChange func(*Config)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config: failed to create watcher: %w", err)
+	}
+
+	dir := filepath.Dir(c.configFilePath)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("config: failed to watch %q: %w", dir, err)
+	}
+
+	go c.watchLoop(ctx, watcher, onChange)
+	return nil
+}
+
+func (c *Config) watchLoop(ctx context.Context, watcher *fsnotify.Watcher, onChange func(*Config)) {
+	defer watcher.Close()
+
+	target := filepath.Clean(c.configFilePath)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			reloaded, err := Load(c.loadOpts...)
+			if err != nil {
+				pkgLogger.Warn("config reload failed, keeping previous configuration", zap.Error(err))
+				continue
+			}
+
+			pkgLogger.Info("configuration reloaded", zap.String("file", c.configFilePath))
+			onChange(reloaded)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			pkgLogger.Warn("config file watcher error", zap.Error(err))
+		}
+	}
+}
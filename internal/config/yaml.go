@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// duration lets config.yaml write camera_timeout: 30s the same way .env
+// and the environment do, instead of forcing nanoseconds. yaml.v3 has no
+// built-in support for unmarshalling a scalar into time.Duration, so this
+// wrapper supplies it and yamlConfig uses *duration in place of
+// *time.Duration.
+type duration time.Duration
+
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	if parsed, err := time.ParseDuration(node.Value); err == nil {
+		*d = duration(parsed)
+		return nil
+	}
+	if secs, err := strconv.Atoi(node.Value); err == nil {
+		*d = duration(time.Duration(secs) * time.Second)
+		return nil
+	}
+	return fmt.Errorf("invalid duration %q", node.Value)
+}
+
+// yamlConfig mirrors the subset of Config worth setting via config.yaml.
+// Every field is a pointer so applyTo can tell "not present in this file"
+// (leave the layer underneath alone) apart from an explicit zero value
+// like "save_frames: false" or "frame_rate: 0".
+type yamlConfig struct {
+	RTSPURL          *string   `yaml:"rtsp_url"`
+	Username         *string   `yaml:"camera_username"`
+	Password         *string   `yaml:"camera_password"`
+	CameraTimeout    *duration `yaml:"camera_timeout"`
+	FrameRate        *int      `yaml:"frame_rate"`
+	SaveFrames       *bool     `yaml:"save_frames"`
+	OutputDir        *string   `yaml:"output_dir"`
+	FFmpegPath       *string   `yaml:"ffmpeg_path"`
+	DetectionEnabled *bool     `yaml:"detection_enabled"`
+	PreEventSeconds  *duration `yaml:"pre_event_seconds"`
+	PostEventSeconds *duration `yaml:"post_event_seconds"`
+
+	// Cameras, if present, fully replaces cfg.Cameras; there's no
+	// per-field merging against a lower layer for individual list
+	// entries since the list as a whole is what a deployment tunes.
+	Cameras []yamlCameraConfig `yaml:"cameras"`
+
+	APIPort    *int    `yaml:"api_port"`
+	AuthSecret *string `yaml:"auth_secret"`
+
+	MetricsSampleInterval  *duration `yaml:"metrics_sample_interval"`
+	DiskFreeThresholdBytes *int64    `yaml:"disk_free_threshold_bytes"`
+
+	DatabaseURL      *string `yaml:"database_url"`
+	DatabaseHost     *string `yaml:"database_host"`
+	DatabasePort     *int    `yaml:"database_port"`
+	DatabaseUser     *string `yaml:"database_user"`
+	DatabasePassword *string `yaml:"database_password"`
+	DatabaseName     *string `yaml:"database_name"`
+	DatabaseSSLMode  *string `yaml:"database_ssl_mode"`
+
+	DetectionService *yamlDetectionServiceConfig `yaml:"detection_service"`
+	Capture          *yamlCaptureConfig          `yaml:"capture"`
+
+	LogLevel  *string `yaml:"log_level"`
+	LogFormat *string `yaml:"log_format"`
+
+	StorageBackend *string `yaml:"storage_backend"`
+
+	S3Endpoint  *string `yaml:"s3_endpoint"`
+	S3Bucket    *string `yaml:"s3_bucket"`
+	S3AccessKey *string `yaml:"s3_access_key"`
+	S3SecretKey *string `yaml:"s3_secret_key"`
+	S3UseSSL    *bool   `yaml:"s3_use_ssl"`
+
+	WebDAVURL      *string `yaml:"webdav_url"`
+	WebDAVUsername *string `yaml:"webdav_username"`
+	WebDAVPassword *string `yaml:"webdav_password"`
+
+	SSHHost      *string `yaml:"ssh_host"`
+	SSHPort      *int    `yaml:"ssh_port"`
+	SSHUser      *string `yaml:"ssh_user"`
+	SSHPassword  *string `yaml:"ssh_password"`
+	SSHKeyPath   *string `yaml:"ssh_key_path"`
+	SSHRemoteDir *string `yaml:"ssh_remote_dir"`
+
+	RetentionMaxAgeDays     *int    `yaml:"retention_max_age_days"`
+	RetentionMaxSizeGB      *int64  `yaml:"retention_max_size_gb"`
+	RetentionKeepEventsDays *int    `yaml:"retention_keep_events_days"`
+	RetentionMinSeverity    *string `yaml:"retention_min_severity"`
+
+	FrameDedupHamming *int `yaml:"frame_dedup_hamming"`
+}
+
+type yamlDetectionServiceConfig struct {
+	URL                 *string   `yaml:"url"`
+	Timeout             *duration `yaml:"timeout"`
+	MaxRetries          *int      `yaml:"max_retries"`
+	ConfidenceThreshold *float64  `yaml:"confidence_threshold"`
+	SupportsBatch       *bool     `yaml:"supports_batch"`
+}
+
+type yamlCaptureConfig struct {
+	Backend *string `yaml:"backend"`
+}
+
+// yamlCameraConfig mirrors config.CameraConfig. Unlike the rest of
+// yamlConfig, its fields aren't pointers: a cameras: entry is expected to
+// fully describe one camera rather than patch a lower layer's, so there's
+// no "unset" case worth distinguishing from a zero value here.
+type yamlCameraConfig struct {
+	Name             string `yaml:"name"`
+	RTSPURL          string `yaml:"rtsp_url"`
+	Username         string `yaml:"username"`
+	Password         string `yaml:"password"`
+	FrameRate        int    `yaml:"frame_rate"`
+	DetectionProfile string `yaml:"detection_profile"`
+}
+
+// applyTo overlays every field y sets onto cfg. Fields left nil in the
+// YAML file don't touch cfg, so config.yaml only needs to mention the
+// values a deployment actually wants to pin.
+func (y *yamlConfig) applyTo(cfg *Config) {
+	strField(y.RTSPURL, &cfg.RTSPURL)
+	strField(y.Username, &cfg.Username)
+	strField(y.Password, &cfg.Password)
+	durationField(y.CameraTimeout, &cfg.CameraTimeout)
+	intField(y.FrameRate, &cfg.FrameRate)
+	boolField(y.SaveFrames, &cfg.SaveFrames)
+	strField(y.OutputDir, &cfg.OutputDir)
+	strField(y.FFmpegPath, &cfg.FFmpegPath)
+	boolField(y.DetectionEnabled, &cfg.DetectionEnabled)
+	durationField(y.PreEventSeconds, &cfg.PreEventSeconds)
+	durationField(y.PostEventSeconds, &cfg.PostEventSeconds)
+
+	if len(y.Cameras) > 0 {
+		cfg.Cameras = make([]CameraConfig, len(y.Cameras))
+		for i, cam := range y.Cameras {
+			cfg.Cameras[i] = CameraConfig{
+				Name:             cam.Name,
+				RTSPURL:          cam.RTSPURL,
+				Username:         cam.Username,
+				Password:         cam.Password,
+				FrameRate:        cam.FrameRate,
+				DetectionProfile: cam.DetectionProfile,
+			}
+		}
+	}
+
+	intField(y.APIPort, &cfg.APIPort)
+	strField(y.AuthSecret, &cfg.AuthSecret)
+
+	durationField(y.MetricsSampleInterval, &cfg.MetricsSampleInterval)
+	int64Field(y.DiskFreeThresholdBytes, &cfg.DiskFreeThresholdBytes)
+
+	strField(y.DatabaseURL, &cfg.DatabaseURL)
+	strField(y.DatabaseHost, &cfg.DatabaseHost)
+	intField(y.DatabasePort, &cfg.DatabasePort)
+	strField(y.DatabaseUser, &cfg.DatabaseUser)
+	strField(y.DatabasePassword, &cfg.DatabasePassword)
+	strField(y.DatabaseName, &cfg.DatabaseName)
+	strField(y.DatabaseSSLMode, &cfg.DatabaseSSLMode)
+
+	if y.DetectionService != nil {
+		strField(y.DetectionService.URL, &cfg.DetectionService.URL)
+		durationField(y.DetectionService.Timeout, &cfg.DetectionService.Timeout)
+		intField(y.DetectionService.MaxRetries, &cfg.DetectionService.MaxRetries)
+		float64Field(y.DetectionService.ConfidenceThreshold, &cfg.DetectionService.ConfidenceThreshold)
+		boolField(y.DetectionService.SupportsBatch, &cfg.DetectionService.SupportsBatch)
+	}
+	if y.Capture != nil {
+		strField(y.Capture.Backend, &cfg.Capture.Backend)
+	}
+
+	strField(y.LogLevel, &cfg.LogLevel)
+	strField(y.LogFormat, &cfg.LogFormat)
+
+	strField(y.StorageBackend, &cfg.StorageBackend)
+
+	strField(y.S3Endpoint, &cfg.S3Endpoint)
+	strField(y.S3Bucket, &cfg.S3Bucket)
+	strField(y.S3AccessKey, &cfg.S3AccessKey)
+	strField(y.S3SecretKey, &cfg.S3SecretKey)
+	boolField(y.S3UseSSL, &cfg.S3UseSSL)
+
+	strField(y.WebDAVURL, &cfg.WebDAVURL)
+	strField(y.WebDAVUsername, &cfg.WebDAVUsername)
+	strField(y.WebDAVPassword, &cfg.WebDAVPassword)
+
+	strField(y.SSHHost, &cfg.SSHHost)
+	intField(y.SSHPort, &cfg.SSHPort)
+	strField(y.SSHUser, &cfg.SSHUser)
+	strField(y.SSHPassword, &cfg.SSHPassword)
+	strField(y.SSHKeyPath, &cfg.SSHKeyPath)
+	strField(y.SSHRemoteDir, &cfg.SSHRemoteDir)
+
+	intField(y.RetentionMaxAgeDays, &cfg.RetentionMaxAgeDays)
+	int64Field(y.RetentionMaxSizeGB, &cfg.RetentionMaxSizeGB)
+	intField(y.RetentionKeepEventsDays, &cfg.RetentionKeepEventsDays)
+	strField(y.RetentionMinSeverity, &cfg.RetentionMinSeverity)
+
+	intField(y.FrameDedupHamming, &cfg.FrameDedupHamming)
+}
+
+func strField(src *string, dst *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func intField(src *int, dst *int) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func int64Field(src *int64, dst *int64) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func float64Field(src *float64, dst *float64) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func boolField(src *bool, dst *bool) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func durationField(src *duration, dst *time.Duration) {
+	if src != nil {
+		*dst = time.Duration(*src)
+	}
+}
@@ -0,0 +1,78 @@
+package eventbus
+
+import "sync"
+
+// Event is a single message fanned out to subscribers. Data is the
+// JSON-encoded payload (typically a marshalled storage.Event) so this
+// package has no dependency on the storage package.
+type Event struct {
+	ID   int
+	Type string
+	Data []byte
+}
+
+// subscriberBufferSize bounds how far a subscriber can lag before it is
+// considered slow and dropped.
+const subscriberBufferSize = 16
+
+// Broker fans out events to any number of subscribers over buffered
+// channels. A subscriber that falls behind has its channel closed and is
+// dropped rather than blocking publishers (drop-slow-consumer semantics).
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int]chan Event
+	nextID      int
+}
+
+// NewBroker creates an empty in-process event broker.
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[int]chan Event)}
+}
+
+// Subscribe registers a new subscriber and returns its channel plus an
+// unsubscribe function the caller must invoke when done listening.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, subscriberBufferSize)
+	b.subscribers[id] = ch
+
+	return ch, func() { b.unsubscribe(id) }
+}
+
+func (b *Broker) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ch, ok := b.subscribers[id]; ok {
+		delete(b.subscribers, id)
+		close(ch)
+	}
+}
+
+// Publish fans an event out to every current subscriber without blocking.
+// Subscribers whose buffer is full are dropped instead of stalling the
+// publisher.
+func (b *Broker) Publish(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, ch := range b.subscribers {
+		select {
+		case ch <- evt:
+		default:
+			delete(b.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// Subscribers returns the current subscriber count.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
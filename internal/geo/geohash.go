@@ -0,0 +1,64 @@
+// Package geo provides the minimal geospatial indexing the storage layer
+// needs to bucket frames and detections into map/timeline cells, without
+// pulling in a full S2 dependency.
+package geo
+
+import "strings"
+
+const base32Alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxPrecision is the longest geohash this package will produce; 12
+// characters resolves to sub-meter precision, far finer than any camera
+// placement needs.
+const MaxPrecision = 12
+
+// Encode computes the geohash string for a lat/lon pair at the given
+// precision (number of base32 characters). Cells sharing a prefix of length
+// N cover the same larger geographic tile, which is what lets
+// GetFrameClusters group frames with a single `substr(cell_id, 1, N)`.
+// A precision of zero or less, or above MaxPrecision, yields a
+// MaxPrecision-character hash.
+func Encode(lat, lon float64, precision int) string {
+	if precision <= 0 {
+		precision = MaxPrecision
+	}
+	if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	var buf strings.Builder
+	bit, ch, evenBit := 0, 0, true
+
+	for buf.Len() < precision {
+		if evenBit {
+			mid := (lonRange[0] + lonRange[1]) / 2
+			if lon >= mid {
+				ch |= 1 << (4 - bit)
+				lonRange[0] = mid
+			} else {
+				lonRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				latRange[0] = mid
+			} else {
+				latRange[1] = mid
+			}
+		}
+		evenBit = !evenBit
+
+		if bit < 4 {
+			bit++
+		} else {
+			buf.WriteByte(base32Alphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return buf.String()
+}
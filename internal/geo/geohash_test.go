@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEncodeKnownVector checks Encode against the textbook geohash example
+// (57.64911, 10.40744 -> "u4pruydqqvj8" at 12 characters), to catch an
+// off-by-one in the bit-interleaving or base32 alphabet.
+func TestEncodeKnownVector(t *testing.T) {
+	want := "u4pruydqqvj8"
+	if got := Encode(57.64911, 10.40744, MaxPrecision); got != want {
+		t.Errorf("Encode(57.64911, 10.40744, %d) = %q, want %q", MaxPrecision, got, want)
+	}
+}
+
+// TestEncodePrefixStable checks that a shorter-precision geohash is always
+// a prefix of the full-precision one for the same coordinates -- the
+// property GetFrameClusters relies on when it groups by substr(cell_id, 1, N).
+func TestEncodePrefixStable(t *testing.T) {
+	full := Encode(57.64911, 10.40744, MaxPrecision)
+	for precision := 1; precision <= MaxPrecision; precision++ {
+		got := Encode(57.64911, 10.40744, precision)
+		if got != full[:precision] {
+			t.Errorf("Encode(..., %d) = %q, want prefix %q of full hash %q", precision, got, full[:precision], full)
+		}
+	}
+}
+
+// TestEncodePrecisionClamping checks the documented clamping behavior for
+// out-of-range precision values.
+func TestEncodePrecisionClamping(t *testing.T) {
+	full := Encode(57.64911, 10.40744, MaxPrecision)
+
+	if got := Encode(57.64911, 10.40744, 0); got != full {
+		t.Errorf("Encode(..., 0) = %q, want default full-precision hash %q", got, full)
+	}
+	if got := Encode(57.64911, 10.40744, MaxPrecision+5); got != full {
+		t.Errorf("Encode(..., MaxPrecision+5) = %q, want clamped to %q", got, full)
+	}
+}
+
+// TestEncodeAlphabet checks every character Encode emits belongs to the
+// base32 alphabet geohash uses.
+func TestEncodeAlphabet(t *testing.T) {
+	got := Encode(-33.8678, 151.2073, MaxPrecision)
+	for _, ch := range got {
+		if !strings.ContainsRune(base32Alphabet, ch) {
+			t.Errorf("Encode produced out-of-alphabet character %q in %q", ch, got)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Package logging builds the zap.Logger used across storage.Service from
+// config.Config's LogLevel/LogFormat, so capture, detection, and storage
+// all log through the same structured, filterable pipeline instead of
+// ad-hoc log.Printf calls.
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New builds a zap.Logger for the given level ("debug", "info", "warn",
+// "error") and format ("json" or "console"). An unrecognized level falls
+// back to info; anything other than "json" falls back to console, which
+// is friendlier for local development than JSON.
+func New(level, format string) (*zap.Logger, error) {
+	var zapLevel zapcore.Level
+	if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
+		zapLevel = zapcore.InfoLevel
+	}
+
+	cfg := zap.NewDevelopmentConfig()
+	if format == "json" {
+		cfg = zap.NewProductionConfig()
+	}
+	cfg.Level = zap.NewAtomicLevelAt(zapLevel)
+
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, fmt.Errorf("logging: failed to build zap logger: %w", err)
+	}
+	return logger, nil
+}
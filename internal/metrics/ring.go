@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is one point of hardware/pipeline telemetry, as stored in the
+// ring buffer and served by GET /api/stats/hardware.
+type Sample struct {
+	Timestamp     time.Time       `json:"timestamp"`
+	CPUPercent    float64         `json:"cpu_percent"`
+	MemoryPercent float64         `json:"memory_percent"`
+	DiskUsedBytes int64           `json:"disk_used_bytes"`
+	DiskFreeBytes int64           `json:"disk_free_bytes"`
+	CameraFPS     map[int]float64 `json:"camera_fps"`
+	BacklogSize   int             `json:"backlog_size"`
+	DroppedFrames int64           `json:"dropped_frames"`
+}
+
+// RingBuffer holds a bounded history of the most recent samples, kept
+// oldest first.
+type RingBuffer struct {
+	mu       sync.Mutex
+	samples  []Sample
+	capacity int
+}
+
+// NewRingBuffer creates a RingBuffer holding at most capacity samples.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{capacity: capacity}
+}
+
+// Add appends a sample, evicting the oldest one once the buffer is full.
+func (rb *RingBuffer) Add(s Sample) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.samples = append(rb.samples, s)
+	if len(rb.samples) > rb.capacity {
+		rb.samples = rb.samples[len(rb.samples)-rb.capacity:]
+	}
+}
+
+// Snapshot returns a copy of the buffer's current contents, oldest first.
+func (rb *RingBuffer) Snapshot() []Sample {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	out := make([]Sample, len(rb.samples))
+	copy(out, rb.samples)
+	return out
+}
+
+// Latest returns the most recently added sample, and false if the buffer
+// is empty.
+func (rb *RingBuffer) Latest() (Sample, bool) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if len(rb.samples) == 0 {
+		return Sample{}, false
+	}
+	return rb.samples[len(rb.samples)-1], true
+}
@@ -0,0 +1,214 @@
+// Package metrics periodically samples hardware and pipeline health (CPU,
+// memory, disk, per-camera FPS, processing backlog, dropped frames) into a
+// bounded ring buffer, and flushes a daily aggregate into storage.SystemStats.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"camera-detection-project/internal/storage"
+)
+
+// defaultRingCapacity bounds the in-memory sample history; at a 15s
+// interval this covers roughly 6 hours.
+const defaultRingCapacity = 1440
+
+// Sampler periodically collects a Sample and appends it to its RingBuffer,
+// flushing a daily SystemStats aggregate whenever the calendar date
+// advances.
+type Sampler struct {
+	storage           *storage.Service
+	outputDir         string
+	interval          time.Duration
+	diskFreeThreshold int64
+	ring              *RingBuffer
+
+	startedAt       time.Time
+	lastSampleAt    time.Time
+	lastFlushDate   string
+	prevCPU         cpuTimes
+	lowDiskNotified bool
+	droppedFrames   int64 // accessed atomically
+}
+
+// NewSampler creates a Sampler that watches outputDir's filesystem for disk
+// usage and emits an EventTypeStorageFull event the first time free space
+// drops below diskFreeThresholdBytes (0 disables the check).
+func NewSampler(storageService *storage.Service, outputDir string, interval time.Duration, diskFreeThresholdBytes int64) *Sampler {
+	now := time.Now()
+	return &Sampler{
+		storage:           storageService,
+		outputDir:         outputDir,
+		interval:          interval,
+		diskFreeThreshold: diskFreeThresholdBytes,
+		ring:              NewRingBuffer(defaultRingCapacity),
+		startedAt:         now,
+		lastSampleAt:      now,
+	}
+}
+
+// Ring returns the sampler's bounded sample history, for the hardware
+// stats endpoint.
+func (s *Sampler) Ring() *RingBuffer {
+	return s.ring
+}
+
+// RecordDroppedFrame increments the dropped-frame counter included in the
+// next sample. Capture pipelines call this when a frame is discarded
+// instead of persisted (e.g. a full backlog).
+func (s *Sampler) RecordDroppedFrame() {
+	atomic.AddInt64(&s.droppedFrames, 1)
+}
+
+// Run samples metrics every interval until ctx is cancelled.
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *Sampler) tick() {
+	now := time.Now()
+	sample := s.collect(now)
+	s.ring.Add(sample)
+	s.checkDiskFull(sample)
+
+	date := now.Format("2006-01-02")
+	if s.lastFlushDate != "" && s.lastFlushDate != date {
+		s.flushDaily(s.lastFlushDate)
+	}
+	s.lastFlushDate = date
+	s.lastSampleAt = now
+}
+
+func (s *Sampler) collect(now time.Time) Sample {
+	var cpuPct float64
+	if cur, err := readCPUTimes(); err != nil {
+		log.Printf("metrics: failed to read CPU times: %v", err)
+	} else {
+		if s.prevCPU.total != 0 {
+			cpuPct = cpuPercent(s.prevCPU, cur)
+		}
+		s.prevCPU = cur
+	}
+
+	memPct, err := readMemoryPercent()
+	if err != nil {
+		log.Printf("metrics: failed to read memory usage: %v", err)
+	}
+
+	used, free, err := diskUsage(s.outputDir)
+	if err != nil {
+		log.Printf("metrics: failed to read disk usage for %s: %v", s.outputDir, err)
+	}
+
+	backlog := 0
+	if frames, err := s.storage.GetUnprocessedFrames(1000); err != nil {
+		log.Printf("metrics: failed to get unprocessed frame backlog: %v", err)
+	} else {
+		backlog = len(frames)
+	}
+
+	return Sample{
+		Timestamp:     now,
+		CPUPercent:    cpuPct,
+		MemoryPercent: memPct,
+		DiskUsedBytes: used,
+		DiskFreeBytes: free,
+		CameraFPS:     s.cameraFPS(now),
+		BacklogSize:   backlog,
+		DroppedFrames: atomic.LoadInt64(&s.droppedFrames),
+	}
+}
+
+// cameraFPS estimates each camera's frame rate since the last sample by
+// counting the frames it produced over that window.
+func (s *Sampler) cameraFPS(now time.Time) map[int]float64 {
+	cameras, err := s.storage.ListAllCameras()
+	if err != nil {
+		log.Printf("metrics: failed to list cameras: %v", err)
+		return nil
+	}
+
+	window := now.Sub(s.lastSampleAt).Seconds()
+	if window <= 0 {
+		window = s.interval.Seconds()
+	}
+
+	fps := make(map[int]float64, len(cameras))
+	for _, camera := range cameras {
+		frames, err := s.storage.GetFramesByTimeRange(camera.ID, s.lastSampleAt, now, 10000)
+		if err != nil {
+			continue
+		}
+		fps[camera.ID] = float64(len(frames)) / window
+	}
+	return fps
+}
+
+// checkDiskFull emits an EventTypeStorageFull event the first time free
+// disk space drops below the configured threshold, and re-arms once it
+// recovers.
+func (s *Sampler) checkDiskFull(sample Sample) {
+	if s.diskFreeThreshold <= 0 {
+		return
+	}
+
+	if sample.DiskFreeBytes >= s.diskFreeThreshold {
+		s.lowDiskNotified = false
+		return
+	}
+
+	if s.lowDiskNotified {
+		return
+	}
+
+	message := fmt.Sprintf("Free disk space on %s is %d bytes, below the %d byte threshold",
+		s.outputDir, sample.DiskFreeBytes, s.diskFreeThreshold)
+	if err := s.storage.CreateSystemEvent(storage.EventTypeStorageFull, storage.SeverityCritical,
+		"Low disk space", message); err != nil {
+		log.Printf("metrics: failed to create storage_full event: %v", err)
+		return
+	}
+	s.lowDiskNotified = true
+}
+
+// flushDaily aggregates counters for dateStr (format "2006-01-02") into
+// storage.SystemStats.
+func (s *Sampler) flushDaily(dateStr string) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		log.Printf("metrics: failed to parse flush date %q: %v", dateStr, err)
+		return
+	}
+
+	counts, err := s.storage.GetDatabaseStats()
+	if err != nil {
+		log.Printf("metrics: failed to get database stats: %v", err)
+		return
+	}
+
+	storageUsed, err := s.storage.GetStorageUsage()
+	if err != nil {
+		log.Printf("metrics: failed to get storage usage: %v", err)
+	}
+
+	uptime := int(time.Since(s.startedAt).Seconds())
+
+	if err := s.storage.UpsertSystemStats(date, counts["recordings"], counts["frames"],
+		counts["detections"], storageUsed, uptime); err != nil {
+		log.Printf("metrics: failed to upsert system stats: %v", err)
+	}
+}
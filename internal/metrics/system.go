@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// cpuTimes holds the counters read from /proc/stat's aggregate "cpu" line,
+// in USER_HZ ticks since boot.
+type cpuTimes struct {
+	idle  uint64
+	total uint64
+}
+
+// readCPUTimes reads the current aggregate CPU tick counters from
+// /proc/stat. Two readings a known interval apart let cpuPercent compute a
+// utilization percentage.
+func readCPUTimes() (cpuTimes, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuTimes{}, fmt.Errorf("failed to open /proc/stat: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return cpuTimes{}, fmt.Errorf("empty /proc/stat")
+	}
+
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 8 || fields[0] != "cpu" {
+		return cpuTimes{}, fmt.Errorf("unexpected /proc/stat format")
+	}
+
+	var total uint64
+	for _, f := range fields[1:] {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return cpuTimes{}, fmt.Errorf("failed to parse /proc/stat field %q: %w", f, err)
+		}
+		total += v
+	}
+
+	idle, err := strconv.ParseUint(fields[4], 10, 64)
+	if err != nil {
+		return cpuTimes{}, fmt.Errorf("failed to parse idle time: %w", err)
+	}
+
+	return cpuTimes{idle: idle, total: total}, nil
+}
+
+// cpuPercent computes CPU utilization between two readings of
+// readCPUTimes.
+func cpuPercent(prev, cur cpuTimes) float64 {
+	totalDelta := float64(cur.total - prev.total)
+	if totalDelta <= 0 {
+		return 0
+	}
+	idleDelta := float64(cur.idle - prev.idle)
+	return (totalDelta - idleDelta) / totalDelta * 100
+}
+
+// readMemoryPercent reads used-memory percentage from /proc/meminfo.
+func readMemoryPercent() (float64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open /proc/meminfo: %w", err)
+	}
+	defer file.Close()
+
+	var total, available uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.TrimSuffix(fields[0], ":") {
+		case "MemTotal":
+			total, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "MemAvailable":
+			available, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+
+	if total == 0 {
+		return 0, fmt.Errorf("could not find MemTotal in /proc/meminfo")
+	}
+
+	return (1 - float64(available)/float64(total)) * 100, nil
+}
+
+// diskUsage reports used and free bytes on the filesystem containing path.
+func diskUsage(path string) (used, free int64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, fmt.Errorf("failed to stat filesystem for %s: %w", path, err)
+	}
+
+	free = int64(stat.Bavail) * int64(stat.Bsize)
+	total := int64(stat.Blocks) * int64(stat.Bsize)
+	used = total - free
+	return used, free, nil
+}
@@ -0,0 +1,26 @@
+// Package packets defines the codec-agnostic packet representation shared
+// by every capture backend (camera.RTSPClient implementation), so
+// downstream consumers - the frame extractor, the MP4 segmenter, and
+// future WebRTC/HLS muxers - can be fed uniformly regardless of which
+// backend produced the data.
+package packets
+
+import "time"
+
+// Packet is a single encoded access unit (an RTP payload's worth of data,
+// reassembled to a frame boundary) read off an RTSPClient.
+type Packet struct {
+	Timestamp  time.Time
+	IsKeyframe bool
+	Codec      string
+	Data       []byte
+}
+
+// Stream describes one media stream negotiated from an RTSP session's SDP,
+// as returned by RTSPClient.Describe.
+type Stream struct {
+	Codec     string
+	Timescale uint32
+	Width     int
+	Height    int
+}
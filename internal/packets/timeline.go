@@ -0,0 +1,138 @@
+package packets
+
+import (
+	"sync"
+	"time"
+)
+
+// Timeline is a lock-guarded ring of recent Packets indexed by timestamp,
+// used to snapshot a clip that starts *before* the moment a detection
+// fires. Its Snapshot doesn't just look backward: it also waits for and
+// collects packets that arrive *after* the trigger, so a single call
+// yields both the pre-roll and the post-roll of an event.
+//
+// Eviction only ever drops down to the nearest preceding keyframe, never
+// into the middle of a GOP, so anything Timeline still holds remains
+// independently decodable.
+type Timeline struct {
+	mu      sync.Mutex
+	packets []Packet
+	window  time.Duration
+	waiters []*snapshotWaiter
+}
+
+// snapshotWaiter accumulates packets arriving after a Snapshot call until
+// deadline, at which point it is closed exactly once.
+type snapshotWaiter struct {
+	deadline  time.Time
+	collected []Packet
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (w *snapshotWaiter) close() {
+	w.closeOnce.Do(func() { close(w.done) })
+}
+
+// NewTimeline creates a Timeline that retains roughly the last `window` of
+// packets for use as pre-roll.
+func NewTimeline(window time.Duration) *Timeline {
+	return &Timeline{window: window}
+}
+
+// Add appends a newly captured packet, evicts anything older than the
+// retention window down to the nearest keyframe, and feeds the packet to
+// any Snapshot calls currently collecting post-roll.
+func (t *Timeline) Add(p Packet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.packets = append(t.packets, p)
+	t.evict(p.Timestamp)
+
+	remaining := t.waiters[:0]
+	for _, w := range t.waiters {
+		if p.Timestamp.Before(w.deadline) {
+			w.collected = append(w.collected, p)
+			remaining = append(remaining, w)
+			continue
+		}
+		w.close()
+	}
+	t.waiters = remaining
+}
+
+// evict drops packets older than the retention window, but only down to
+// the nearest preceding keyframe, so the retained packets always start
+// with a keyframe and remain independently decodable.
+func (t *Timeline) evict(now time.Time) {
+	cutoff := now.Add(-t.window)
+
+	firstRecent := len(t.packets) - 1
+	for i, p := range t.packets {
+		if !p.Timestamp.Before(cutoff) {
+			firstRecent = i
+			break
+		}
+	}
+
+	keep := 0
+	for i := firstRecent; i >= 0; i-- {
+		if t.packets[i].IsKeyframe {
+			keep = i
+			break
+		}
+	}
+
+	t.packets = t.packets[keep:]
+}
+
+// Snapshot returns an ordered, decodable slice of packets spanning from
+// preSeconds before the call to postSeconds after it: the already-buffered
+// pre-roll, walked back to its nearest keyframe, followed by whatever
+// arrives via Add over the next postSeconds. It blocks until postSeconds
+// has elapsed.
+func (t *Timeline) Snapshot(preSeconds, postSeconds time.Duration) []Packet {
+	now := time.Now()
+	cutoff := now.Add(-preSeconds)
+
+	t.mu.Lock()
+	firstRecent := len(t.packets)
+	for i, p := range t.packets {
+		if !p.Timestamp.Before(cutoff) {
+			firstRecent = i
+			break
+		}
+	}
+
+	start := 0
+	for i := firstRecent - 1; i >= 0; i-- {
+		if t.packets[i].IsKeyframe {
+			start = i
+			break
+		}
+	}
+
+	pre := make([]Packet, len(t.packets)-start)
+	copy(pre, t.packets[start:])
+
+	if postSeconds <= 0 {
+		t.mu.Unlock()
+		return pre
+	}
+
+	waiter := &snapshotWaiter{
+		deadline: now.Add(postSeconds),
+		done:     make(chan struct{}),
+	}
+	t.waiters = append(t.waiters, waiter)
+	t.mu.Unlock()
+
+	// Streams can stall, so don't rely solely on Add to ever observe a
+	// packet past the deadline - a timer guarantees Snapshot still returns.
+	timer := time.AfterFunc(postSeconds, waiter.close)
+	defer timer.Stop()
+
+	<-waiter.done
+	return append(pre, waiter.collected...)
+}
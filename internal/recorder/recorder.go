@@ -0,0 +1,90 @@
+// Package recorder muxes a slice of packets captured around a detection
+// into a standalone clip file, and records it against the event that
+// triggered it. It's the consumer of packets.Timeline.Snapshot: a camera
+// backend buffers pre-roll continuously, and on a positive detection hands
+// the snapshot here to become a `recordings` row with clip_type "event".
+package recorder
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nareix/joy4/av"
+	"github.com/nareix/joy4/format/mp4"
+
+	"camera-detection-project/internal/packets"
+	"camera-detection-project/internal/storage"
+)
+
+// StorageService is the narrow slice of storage.Service that WriteClip
+// needs, mirroring camera.StorageService so either an RTSP capture backend
+// or a *storage.Service itself can be passed directly.
+type StorageService interface {
+	CreateEventRecording(cameraID, eventID int, filePath string, fileSize int64, duration int) (*storage.Recording, error)
+}
+
+// WriteClip muxes pkts into an fMP4 file at path and records it as an event
+// clip recording linked to eventID. Only H.264 is supported today, matching
+// the only codec the capture backends currently describe.
+func WriteClip(storageService StorageService, cameraID, eventID int, path string, pkts []packets.Packet, stream packets.Stream) (*storage.Recording, error) {
+	fileSize, duration, err := MuxClip(path, pkts, stream)
+	if err != nil {
+		return nil, err
+	}
+	return storageService.CreateEventRecording(cameraID, eventID, path, fileSize, duration)
+}
+
+// MuxClip muxes pkts into an fMP4 file at path without touching storage, for
+// callers (e.g. the test-camera CLI) that just want a clip file on disk. It
+// returns the written file's size and duration in seconds. Only H.264 is
+// supported today, matching the only codec the capture backends currently
+// describe.
+func MuxClip(path string, pkts []packets.Packet, stream packets.Stream) (fileSize int64, duration int, err error) {
+	if stream.Codec != "h264" {
+		return 0, 0, fmt.Errorf("recorder: unsupported codec %q", stream.Codec)
+	}
+	if len(pkts) == 0 {
+		return 0, 0, fmt.Errorf("recorder: no packets to write")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("recorder: failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	muxer := mp4.NewMuxer(file)
+	if err := muxer.WriteHeader([]av.CodecData{h264CodecData{}}); err != nil {
+		return 0, 0, fmt.Errorf("recorder: failed to write MP4 header: %w", err)
+	}
+
+	start := pkts[0].Timestamp
+	for _, p := range pkts {
+		if err := muxer.WritePacket(av.Packet{
+			IsKeyFrame: p.IsKeyframe,
+			Time:       p.Timestamp.Sub(start),
+			Data:       p.Data,
+		}); err != nil {
+			return 0, 0, fmt.Errorf("recorder: failed to write packet: %w", err)
+		}
+	}
+
+	if err := muxer.WriteTrailer(); err != nil {
+		return 0, 0, fmt.Errorf("recorder: failed to write MP4 trailer: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("recorder: failed to stat %s: %w", path, err)
+	}
+
+	duration = int(pkts[len(pkts)-1].Timestamp.Sub(start).Seconds())
+	return info.Size(), duration, nil
+}
+
+// h264CodecData is a minimal av.CodecData for an H.264 stream whose
+// SPS/PPS the muxer picks up from the packet stream itself, mirroring the
+// same simplification broadcast.avStreamsFromDescribe makes for RTMP.
+type h264CodecData struct{}
+
+func (h264CodecData) Type() av.CodecType { return av.H264 }
@@ -0,0 +1,64 @@
+// Package backend abstracts where recording and frame bytes actually live,
+// so Service can keep writing through the same small interface whether
+// they're on local disk, an S3-compatible object store, a WebDAV share, or
+// an SSH/SFTP host. This is what lets the recorder run on a small edge box
+// while months of footage accumulate somewhere else entirely.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"camera-detection-project/internal/config"
+)
+
+// Info describes one stored object, as returned by Stat and List.
+type Info struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Backend stores blobs under backend-relative path keys (e.g.
+// "frames/cam_1/frame_20260727_120000.jpg") and can stat, list, and delete
+// them; it has no method for reading a blob's contents back. Every
+// implementation is responsible for creating any intermediate directories/
+// collections/prefixes a key implies.
+type Backend interface {
+	// Put writes the contents of r to key, overwriting it if it already
+	// exists, and returns the number of bytes written.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// Stat returns metadata for key without reading its contents.
+	Stat(key string) (Info, error)
+
+	// Delete removes key. Deleting a key that doesn't exist is not an
+	// error.
+	Delete(key string) error
+
+	// List returns every key starting with prefix.
+	List(prefix string) ([]Info, error)
+
+	// Usage returns the total size in bytes of everything this backend
+	// holds, for Service.GetStorageUsage.
+	Usage() (int64, error)
+}
+
+// New builds the Backend selected by cfg.StorageBackend ("local" by
+// default, "s3", "webdav", or "ssh"/"sftp").
+func New(cfg *config.Config) (Backend, error) {
+	switch cfg.StorageBackend {
+	case "", "local":
+		return NewLocalBackend(cfg.OutputDir)
+	case "s3":
+		return NewS3Backend(cfg)
+	case "webdav":
+		return NewWebDAVBackend(cfg)
+	case "ssh", "sftp":
+		return NewSSHBackend(cfg)
+	default:
+		return nil, fmt.Errorf("backend: unknown storage backend %q", cfg.StorageBackend)
+	}
+}
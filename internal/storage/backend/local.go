@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalBackend stores blobs as plain files under root, keyed by their
+// path relative to it. It's the default backend, and the only one that
+// keeps everything on the box running the recorder.
+type LocalBackend struct {
+	root string
+}
+
+// NewLocalBackend creates root if it doesn't exist yet and returns a
+// Backend rooted there.
+func NewLocalBackend(root string) (*LocalBackend, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("backend: failed to create local root %q: %w", root, err)
+	}
+	return &LocalBackend{root: root}, nil
+}
+
+func (b *LocalBackend) path(key string) string {
+	return filepath.Join(b.root, filepath.FromSlash(key))
+}
+
+func (b *LocalBackend) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("backend: failed to create directory for %q: %w", key, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("backend: failed to create %q: %w", key, err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return 0, fmt.Errorf("backend: failed to write %q: %w", key, err)
+	}
+	return n, nil
+}
+
+func (b *LocalBackend) Stat(key string) (Info, error) {
+	fi, err := os.Stat(b.path(key))
+	if err != nil {
+		return Info{}, fmt.Errorf("backend: stat %q failed: %w", key, err)
+	}
+	return Info{Key: key, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (b *LocalBackend) Delete(key string) error {
+	if err := os.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("backend: delete %q failed: %w", key, err)
+	}
+	return nil
+}
+
+func (b *LocalBackend) List(prefix string) ([]Info, error) {
+	var infos []Info
+
+	err := filepath.Walk(b.path(prefix), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil // skip files we can't access, same as GetStorageUsage did before
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.root, path)
+		if err != nil {
+			return nil
+		}
+		infos = append(infos, Info{Key: filepath.ToSlash(rel), Size: fi.Size(), ModTime: fi.ModTime()})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("backend: list %q failed: %w", prefix, err)
+	}
+	return infos, nil
+}
+
+func (b *LocalBackend) Usage() (int64, error) {
+	var total int64
+
+	err := filepath.Walk(b.root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !fi.IsDir() {
+			total += fi.Size()
+		}
+		return nil
+	})
+	return total, err
+}
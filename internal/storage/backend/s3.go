@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"camera-detection-project/internal/config"
+)
+
+// S3Backend stores blobs in a bucket on any S3-compatible object store
+// (AWS S3 itself, or a self-hosted MinIO instance), via S3_ENDPOINT/
+// S3_BUCKET/S3_ACCESS_KEY/S3_SECRET_KEY/S3_USE_SSL.
+type S3Backend struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Backend connects to the endpoint and bucket named by cfg. It doesn't
+// create the bucket; that's expected to already exist.
+func NewS3Backend(cfg *config.Config) (*S3Backend, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("backend: S3_ENDPOINT and S3_BUCKET are required for the s3 backend")
+	}
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("backend: failed to create S3 client for %q: %w", cfg.S3Endpoint, err)
+	}
+
+	return &S3Backend{client: client, bucket: cfg.S3Bucket}, nil
+}
+
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	info, err := b.client.PutObject(ctx, b.bucket, key, r, -1, minio.PutObjectOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("backend: S3 put %q failed: %w", key, err)
+	}
+	return info.Size, nil
+}
+
+func (b *S3Backend) Stat(key string) (Info, error) {
+	info, err := b.client.StatObject(context.Background(), b.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return Info{}, fmt.Errorf("backend: S3 stat %q failed: %w", key, err)
+	}
+	return Info{Key: key, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+func (b *S3Backend) Delete(key string) error {
+	if err := b.client.RemoveObject(context.Background(), b.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("backend: S3 delete %q failed: %w", key, err)
+	}
+	return nil
+}
+
+func (b *S3Backend) List(prefix string) ([]Info, error) {
+	ctx := context.Background()
+	var infos []Info
+
+	for obj := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("backend: S3 list %q failed: %w", prefix, obj.Err)
+		}
+		infos = append(infos, Info{Key: obj.Key, Size: obj.Size, ModTime: obj.LastModified})
+	}
+	return infos, nil
+}
+
+func (b *S3Backend) Usage() (int64, error) {
+	infos, err := b.List("")
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+	}
+	return total, nil
+}
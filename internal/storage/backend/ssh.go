@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"camera-detection-project/internal/config"
+)
+
+// SSHBackend stores blobs on a remote host over SFTP, via SSH_HOST/
+// SSH_PORT/SSH_USER and either SSH_KEY_PATH or SSH_PASSWORD, under
+// SSH_REMOTE_DIR.
+type SSHBackend struct {
+	sshClient *ssh.Client
+	client    *sftp.Client
+	root      string
+}
+
+// NewSSHBackend dials cfg.SSHHost and opens an SFTP session on top of it.
+func NewSSHBackend(cfg *config.Config) (*SSHBackend, error) {
+	if cfg.SSHHost == "" {
+		return nil, fmt.Errorf("backend: SSH_HOST is required for the ssh backend")
+	}
+
+	var authMethods []ssh.AuthMethod
+	if cfg.SSHKeyPath != "" {
+		key, err := os.ReadFile(cfg.SSHKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("backend: failed to read SSH key %q: %w", cfg.SSHKeyPath, err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("backend: failed to parse SSH key %q: %w", cfg.SSHKeyPath, err)
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+	if cfg.SSHPassword != "" {
+		authMethods = append(authMethods, ssh.Password(cfg.SSHPassword))
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User: cfg.SSHUser,
+		Auth: authMethods,
+		// Edge boxes backing up to a remote host rarely ship a known_hosts
+		// entry for it; this trades host-key pinning for "it just works".
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         10 * time.Second,
+	}
+
+	addr := fmt.Sprintf("%s:%d", cfg.SSHHost, cfg.SSHPort)
+	sshClient, err := ssh.Dial("tcp", addr, sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("backend: failed to dial SSH host %q: %w", addr, err)
+	}
+
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("backend: failed to start SFTP session on %q: %w", addr, err)
+	}
+
+	root := cfg.SSHRemoteDir
+	if root == "" {
+		root = "."
+	}
+
+	return &SSHBackend{sshClient: sshClient, client: sftpClient, root: root}, nil
+}
+
+func (b *SSHBackend) path(key string) string {
+	return path.Join(b.root, key)
+}
+
+func (b *SSHBackend) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	remotePath := b.path(key)
+	if err := b.client.MkdirAll(path.Dir(remotePath)); err != nil {
+		return 0, fmt.Errorf("backend: sftp mkdir for %q failed: %w", key, err)
+	}
+
+	f, err := b.client.Create(remotePath)
+	if err != nil {
+		return 0, fmt.Errorf("backend: sftp create %q failed: %w", key, err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return 0, fmt.Errorf("backend: sftp write %q failed: %w", key, err)
+	}
+	return n, nil
+}
+
+func (b *SSHBackend) Stat(key string) (Info, error) {
+	fi, err := b.client.Stat(b.path(key))
+	if err != nil {
+		return Info{}, fmt.Errorf("backend: sftp stat %q failed: %w", key, err)
+	}
+	return Info{Key: key, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (b *SSHBackend) Delete(key string) error {
+	if err := b.client.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("backend: sftp delete %q failed: %w", key, err)
+	}
+	return nil
+}
+
+func (b *SSHBackend) List(prefix string) ([]Info, error) {
+	var infos []Info
+
+	walker := b.client.Walk(b.path(prefix))
+	for walker.Step() {
+		if walker.Err() != nil {
+			continue // skip entries we can't stat, same tolerance LocalBackend.List has
+		}
+		if walker.Stat().IsDir() {
+			continue
+		}
+
+		rel, err := filepath.Rel(b.root, walker.Path())
+		if err != nil {
+			continue
+		}
+		infos = append(infos, Info{Key: filepath.ToSlash(rel), Size: walker.Stat().Size(), ModTime: walker.Stat().ModTime()})
+	}
+	return infos, nil
+}
+
+func (b *SSHBackend) Usage() (int64, error) {
+	infos, err := b.List("")
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+	}
+	return total, nil
+}
+
+// Close shuts down the SFTP session and its underlying SSH connection.
+// Service.Close calls this via an io.Closer type assertion.
+func (b *SSHBackend) Close() error {
+	b.client.Close()
+	return b.sshClient.Close()
+}
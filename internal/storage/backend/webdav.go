@@ -0,0 +1,229 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"camera-detection-project/internal/config"
+)
+
+// WebDAVBackend stores blobs on a WebDAV share via WEBDAV_URL (and optional
+// WEBDAV_USERNAME/WEBDAV_PASSWORD for basic auth).
+type WebDAVBackend struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewWebDAVBackend validates cfg and returns a Backend talking to
+// cfg.WebDAVURL.
+func NewWebDAVBackend(cfg *config.Config) (*WebDAVBackend, error) {
+	if cfg.WebDAVURL == "" {
+		return nil, fmt.Errorf("backend: WEBDAV_URL is required for the webdav backend")
+	}
+
+	return &WebDAVBackend{
+		baseURL:  strings.TrimRight(cfg.WebDAVURL, "/"),
+		username: cfg.WebDAVUsername,
+		password: cfg.WebDAVPassword,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (b *WebDAVBackend) url(key string) string {
+	return b.baseURL + "/" + strings.TrimLeft(key, "/")
+}
+
+func (b *WebDAVBackend) do(req *http.Request) (*http.Response, error) {
+	if b.username != "" {
+		req.SetBasicAuth(b.username, b.password)
+	}
+	return b.client.Do(req)
+}
+
+// mkcolAll issues MKCOL for every ancestor collection of key, so a PUT
+// against a key in a not-yet-existing directory succeeds. A 405 (already
+// exists) is expected and not an error.
+func (b *WebDAVBackend) mkcolAll(key string) error {
+	dir := path.Dir(key)
+	if dir == "." || dir == "/" {
+		return nil
+	}
+
+	cur := ""
+	for _, part := range strings.Split(dir, "/") {
+		if part == "" {
+			continue
+		}
+		cur += "/" + part
+
+		req, err := http.NewRequest("MKCOL", b.url(cur), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := b.do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+func (b *WebDAVBackend) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if err := b.mkcolAll(key); err != nil {
+		return 0, fmt.Errorf("backend: webdav mkcol for %q failed: %w", key, err)
+	}
+
+	// net/http needs a Content-Length to stream a PUT without chunked
+	// transfer encoding, which not every WebDAV server accepts well, so
+	// buffer the blob (frames and recording clips, not live video).
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, r)
+	if err != nil {
+		return 0, fmt.Errorf("backend: failed to buffer %q: %w", key, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.url(key), &buf)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return 0, fmt.Errorf("backend: webdav put %q failed: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return 0, fmt.Errorf("backend: webdav put %q returned status %d", key, resp.StatusCode)
+	}
+	return n, nil
+}
+
+func (b *WebDAVBackend) Stat(key string) (Info, error) {
+	req, err := http.NewRequest(http.MethodHead, b.url(key), nil)
+	if err != nil {
+		return Info{}, err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return Info{}, fmt.Errorf("backend: webdav stat %q failed: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Info{}, fmt.Errorf("backend: webdav stat %q returned status %d", key, resp.StatusCode)
+	}
+
+	info := Info{Key: key, Size: resp.ContentLength}
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			info.ModTime = t
+		}
+	}
+	return info, nil
+}
+
+func (b *WebDAVBackend) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, b.url(key), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(req)
+	if err != nil {
+		return fmt.Errorf("backend: webdav delete %q failed: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("backend: webdav delete %q returned status %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+// davMultistatus is the minimal subset of a PROPFIND multistatus response
+// this backend needs: which hrefs are files (not collections) and their
+// size/mtime.
+type davMultistatus struct {
+	Responses []davResponse `xml:"response"`
+}
+
+type davResponse struct {
+	Href     string `xml:"href"`
+	PropStat struct {
+		Prop struct {
+			ContentLength int64  `xml:"getcontentlength"`
+			LastModified  string `xml:"getlastmodified"`
+			ResourceType  struct {
+				Collection *struct{} `xml:"collection"`
+			} `xml:"resourcetype"`
+		} `xml:"prop"`
+	} `xml:"propstat"`
+}
+
+func (b *WebDAVBackend) List(prefix string) ([]Info, error) {
+	body := strings.NewReader(`<?xml version="1.0" encoding="utf-8" ?><D:propfind xmlns:D="DAV:"><D:allprop/></D:propfind>`)
+
+	req, err := http.NewRequest("PROPFIND", b.url(prefix), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "infinity")
+	req.Header.Set("Content-Type", "application/xml")
+
+	resp, err := b.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("backend: webdav propfind %q failed: %w", prefix, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("backend: webdav propfind %q returned status %d", prefix, resp.StatusCode)
+	}
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("backend: failed to decode propfind response for %q: %w", prefix, err)
+	}
+
+	var infos []Info
+	for _, r := range ms.Responses {
+		if r.PropStat.Prop.ResourceType.Collection != nil {
+			continue
+		}
+
+		key := strings.TrimPrefix(r.Href, b.baseURL)
+		key = strings.TrimLeft(key, "/")
+
+		info := Info{Key: key, Size: r.PropStat.Prop.ContentLength}
+		if t, err := http.ParseTime(r.PropStat.Prop.LastModified); err == nil {
+			info.ModTime = t
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (b *WebDAVBackend) Usage() (int64, error) {
+	infos, err := b.List("")
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+	}
+	return total, nil
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Dialect abstracts the SQL differences between the backends this package
+// supports (Postgres and SQLite) so CameraRepository, RecordingRepository,
+// FrameRepository, and EventRepository can be written once against a single
+// placeholder syntax ("?") and run unchanged against either one.
+type Dialect interface {
+	// Name identifies the dialect, e.g. for logging.
+	Name() string
+
+	// Rewrite converts a query written with "?" placeholders into this
+	// dialect's placeholder syntax: unchanged for SQLite, "$1, $2, ..." for
+	// Postgres.
+	Rewrite(query string) string
+
+	// InsertReturningID executes an INSERT built with "?" placeholders and
+	// returns the id of the inserted row. Postgres does this with a
+	// RETURNING clause; SQLite does it via the result's LastInsertId.
+	InsertReturningID(conn *sql.DB, query string, args ...interface{}) (int64, error)
+
+	// InsertReturningIDs executes a multi-row INSERT ... VALUES (...),(...)
+	// built with "?" placeholders and returns the n assigned ids in the same
+	// order as the VALUES rows, for batch writers like FrameRepository's
+	// CreateFramesBatch. Postgres does this with a RETURNING clause (relying
+	// on Postgres returning rows in VALUES order for a plain literal VALUES
+	// list); SQLite has no multi-row RETURNING story here, so it derives the
+	// ids from the last rowid, which is valid as long as nothing else is
+	// inserting into the same table concurrently on another connection.
+	InsertReturningIDs(conn *sql.DB, query string, n int, args ...interface{}) ([]int64, error)
+
+	// AdvisoryLock serializes concurrent migration runs (e.g. two server
+	// instances starting at once) so only one applies pending migrations at
+	// a time. It blocks until the lock is acquired and returns an unlock
+	// func to release it. SQLite has no server-side lock primitive and no
+	// concurrent-writer story worth serializing, so its implementation is a
+	// no-op.
+	AdvisoryLock(conn *sql.DB) (unlock func() error, err error)
+}
+
+// postgresDialect targets PostgreSQL via lib/pq.
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Rewrite(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (d postgresDialect) InsertReturningID(conn *sql.DB, query string, args ...interface{}) (int64, error) {
+	var id int64
+	err := conn.QueryRow(d.Rewrite(query)+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (d postgresDialect) InsertReturningIDs(conn *sql.DB, query string, n int, args ...interface{}) ([]int64, error) {
+	rows, err := conn.Query(d.Rewrite(query)+" RETURNING id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0, n)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// migrationLockKey is an arbitrary fixed key for the session-level advisory
+// lock used to serialize migration runs. Any int64 works as long as it's
+// not reused by some other part of the system for an unrelated lock.
+const migrationLockKey = 851209
+
+func (postgresDialect) AdvisoryLock(conn *sql.DB) (func() error, error) {
+	if _, err := conn.Exec("SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration advisory lock: %w", err)
+	}
+	return func() error {
+		_, err := conn.Exec("SELECT pg_advisory_unlock($1)", migrationLockKey)
+		return err
+	}, nil
+}
+
+// sqliteDialect targets SQLite via modernc.org/sqlite.
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Rewrite(query string) string { return query }
+
+func (d sqliteDialect) InsertReturningID(conn *sql.DB, query string, args ...interface{}) (int64, error) {
+	res, err := conn.Exec(d.Rewrite(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (d sqliteDialect) InsertReturningIDs(conn *sql.DB, query string, n int, args ...interface{}) ([]int64, error) {
+	res, err := conn.Exec(d.Rewrite(query), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, n)
+	for i := 0; i < n; i++ {
+		ids[i] = lastID - int64(n-1) + int64(i)
+	}
+	return ids, nil
+}
+
+func (sqliteDialect) AdvisoryLock(conn *sql.DB) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,28 @@
+package storage
+
+import "testing"
+
+func TestPostgresDialectRewrite(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM cameras WHERE id = ?", "SELECT * FROM cameras WHERE id = $1"},
+		{"UPDATE cameras SET name = ?, rtsp_url = ? WHERE id = ?", "UPDATE cameras SET name = $1, rtsp_url = $2 WHERE id = $3"},
+		{"SELECT 1", "SELECT 1"},
+		{"?, ?, ?", "$1, $2, $3"},
+	}
+
+	for _, c := range cases {
+		if got := (postgresDialect{}).Rewrite(c.query); got != c.want {
+			t.Errorf("postgresDialect.Rewrite(%q) = %q, want %q", c.query, got, c.want)
+		}
+	}
+}
+
+func TestSQLiteDialectRewriteIsNoop(t *testing.T) {
+	query := "SELECT * FROM cameras WHERE id = ? AND name = ?"
+	if got := (sqliteDialect{}).Rewrite(query); got != query {
+		t.Errorf("sqliteDialect.Rewrite(%q) = %q, want unchanged", query, got)
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Tuned like a typical dataloader: short enough that a single frame never
+// waits long for its write, long enough to fold several cameras' frames at
+// 5+ FPS into one round trip.
+const (
+	frameBatchWindow  = 100 * time.Millisecond
+	frameBatchMaxSize = 100
+)
+
+// frameCreateRequest is one pending SaveFrame call waiting on the next
+// CreateFramesBatch flush.
+type frameCreateRequest struct {
+	frame *Frame
+	done  chan error
+}
+
+// frameUpdateRequest is one pending UpdateFrameProcessed call waiting on the
+// next UpdateFramesBatch flush.
+type frameUpdateRequest struct {
+	update FrameProcessedUpdate
+	done   chan error
+}
+
+// frameBatcher coalesces concurrent SaveFrame/UpdateFrameProcessed calls
+// into periodic multi-row INSERT/UPDATE statements against FrameRepository,
+// so the per-frame hot path (multiple cameras, each at 5+ FPS) isn't blocked
+// on a DB round trip per frame. Callers enqueue and block on their own done
+// channel; a single background flush (triggered by frameBatchWindow or by
+// frameBatchMaxSize pending requests, whichever comes first) services
+// everyone queued at that point.
+type frameBatcher struct {
+	repo   *FrameRepository
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	creates []*frameCreateRequest
+	updates []*frameUpdateRequest
+	timer   *time.Timer
+}
+
+func newFrameBatcher(repo *FrameRepository, logger *zap.Logger) *frameBatcher {
+	return &frameBatcher{repo: repo, logger: logger}
+}
+
+// CreateFrame enqueues frame for the next flush and blocks until it lands.
+// On success frame.ID is populated in place, same as a direct
+// FrameRepository.CreateFrame call.
+func (b *frameBatcher) CreateFrame(frame *Frame) error {
+	req := &frameCreateRequest{frame: frame, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.creates = append(b.creates, req)
+	full := len(b.creates)+len(b.updates) >= frameBatchMaxSize
+	b.armLocked()
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+
+	return <-req.done
+}
+
+// UpdateFrameProcessed enqueues update for the next flush and blocks until
+// it lands.
+func (b *frameBatcher) UpdateFrameProcessed(update FrameProcessedUpdate) error {
+	req := &frameUpdateRequest{update: update, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.updates = append(b.updates, req)
+	full := len(b.creates)+len(b.updates) >= frameBatchMaxSize
+	b.armLocked()
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+
+	return <-req.done
+}
+
+// armLocked starts the flush timer if one isn't already pending. Callers
+// must hold b.mu.
+func (b *frameBatcher) armLocked() {
+	if b.timer != nil {
+		return
+	}
+	b.timer = time.AfterFunc(frameBatchWindow, b.flush)
+}
+
+// flush drains whatever creates/updates are currently queued and runs one
+// CreateFramesBatch and/or one UpdateFramesBatch, fanning the result out to
+// every request waiting on it.
+func (b *frameBatcher) flush() {
+	b.mu.Lock()
+	creates := b.creates
+	updates := b.updates
+	b.creates = nil
+	b.updates = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(creates) > 0 {
+		frames := make([]*Frame, len(creates))
+		for i, req := range creates {
+			frames[i] = req.frame
+		}
+
+		err := b.repo.CreateFramesBatch(frames)
+		if err != nil {
+			b.logger.Warn("batched frame insert failed", zap.Int("count", len(frames)), zap.Error(err))
+		}
+		for _, req := range creates {
+			req.done <- err
+		}
+	}
+
+	if len(updates) > 0 {
+		batch := make([]FrameProcessedUpdate, len(updates))
+		for i, req := range updates {
+			batch[i] = req.update
+		}
+
+		err := b.repo.UpdateFramesBatch(batch)
+		if err != nil {
+			b.logger.Warn("batched frame update failed", zap.Int("count", len(batch)), zap.Error(err))
+		}
+		for _, req := range updates {
+			req.done <- err
+		}
+	}
+}
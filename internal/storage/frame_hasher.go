@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"image"
+	_ "image/jpeg"
+	"math"
+	"math/bits"
+	"os"
+	"sort"
+)
+
+const (
+	// phashSize is the side length a frame is downscaled to (grayscale)
+	// before the DCT runs.
+	phashSize = 32
+	// phashBlock is the side of the low-frequency block kept from the
+	// DCT's top-left corner once it's run; phashBlock^2 - 1 of those
+	// coefficients (every one but the DC term) become the hash's bits.
+	phashBlock = 8
+)
+
+// frameHasher computes a 64-bit perceptual hash (pHash) for a JPEG frame:
+// downscale to phashSize x phashSize grayscale, run a 2D DCT, keep the
+// phashBlock x phashBlock block of lowest-frequency coefficients (skipping
+// the DC term, which only reflects overall brightness), and threshold each
+// of the remaining 63 against their median. Two frames of a near-static
+// scene land a handful of bits apart; a real scene change flips most of
+// them. Unlike a cryptographic hash, pHash is designed for this kind of
+// similarity comparison via Hamming distance (see hammingDistance).
+type frameHasher struct{}
+
+func newFrameHasher() *frameHasher {
+	return &frameHasher{}
+}
+
+// Hash reads and decodes the image at path and returns its pHash.
+func (frameHasher) Hash(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, err
+	}
+
+	coeffs := dct2D(downscaleGray(img, phashSize), phashSize)
+
+	vals := make([]float64, 0, phashBlock*phashBlock-1)
+	for y := 0; y < phashBlock; y++ {
+		for x := 0; x < phashBlock; x++ {
+			if x == 0 && y == 0 {
+				continue
+			}
+			vals = append(vals, coeffs[y][x])
+		}
+	}
+	median := medianOf(vals)
+
+	var hash uint64
+	bit := uint(0)
+	for y := 0; y < phashBlock; y++ {
+		for x := 0; x < phashBlock; x++ {
+			if x == 0 && y == 0 {
+				continue
+			}
+			if coeffs[y][x] > median {
+				hash |= 1 << bit
+			}
+			bit++
+		}
+	}
+	return int64(hash), nil
+}
+
+// downscaleGray resizes img to an n x n grayscale matrix, box-averaging the
+// source pixels each destination pixel covers.
+func downscaleGray(img image.Image, n int) [][]float64 {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	out := make([][]float64, n)
+	for y := range out {
+		out[y] = make([]float64, n)
+	}
+
+	for dy := 0; dy < n; dy++ {
+		srcY0, srcY1 := dy*h/n, (dy+1)*h/n
+		if srcY1 <= srcY0 {
+			srcY1 = srcY0 + 1
+		}
+		for dx := 0; dx < n; dx++ {
+			srcX0, srcX1 := dx*w/n, (dx+1)*w/n
+			if srcX1 <= srcX0 {
+				srcX1 = srcX0 + 1
+			}
+
+			var sum float64
+			var count int
+			for sy := srcY0; sy < srcY1 && sy < h; sy++ {
+				for sx := srcX0; sx < srcX1 && sx < w; sx++ {
+					r, g, b, _ := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					count++
+				}
+			}
+			if count > 0 {
+				out[dy][dx] = sum / float64(count)
+			}
+		}
+	}
+	return out
+}
+
+// dct2D runs a separable 2D DCT-II over an n x n matrix: a 1D DCT along
+// each row, then a 1D DCT along each column of the result.
+func dct2D(in [][]float64, n int) [][]float64 {
+	rows := make([][]float64, n)
+	for y := 0; y < n; y++ {
+		rows[y] = dct1D(in[y], n)
+	}
+
+	out := make([][]float64, n)
+	for y := 0; y < n; y++ {
+		out[y] = make([]float64, n)
+	}
+	col := make([]float64, n)
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			col[y] = rows[y][x]
+		}
+		transformed := dct1D(col, n)
+		for y := 0; y < n; y++ {
+			out[y][x] = transformed[y]
+		}
+	}
+	return out
+}
+
+// dct1D runs a 1D DCT-II over a length-n vector.
+func dct1D(in []float64, n int) []float64 {
+	out := make([]float64, n)
+	for k := 0; k < n; k++ {
+		var sum float64
+		for i := 0; i < n; i++ {
+			sum += in[i] * math.Cos(math.Pi/float64(n)*(float64(i)+0.5)*float64(k))
+		}
+		out[k] = sum * dctAlpha(k, n)
+	}
+	return out
+}
+
+// dctAlpha is the DCT-II orthonormal scale factor for coefficient k of n.
+func dctAlpha(k, n int) float64 {
+	if k == 0 {
+		return math.Sqrt(1 / float64(n))
+	}
+	return math.Sqrt(2 / float64(n))
+}
+
+// medianOf returns the median of vals without mutating the caller's slice.
+func medianOf(vals []float64) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// hammingDistance returns the number of differing bits between two pHashes.
+func hammingDistance(a, b int64) int {
+	return bits.OnesCount64(uint64(a) ^ uint64(b))
+}
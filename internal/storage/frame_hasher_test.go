@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestHammingDistance(t *testing.T) {
+	cases := []struct {
+		a, b int64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0b1111, 0b0000, 4},
+		{-1, 0, 64}, // all 64 bits set vs none
+	}
+
+	for _, c := range cases {
+		if got := hammingDistance(c.a, c.b); got != c.want {
+			t.Errorf("hammingDistance(%b, %b) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestMedianOf(t *testing.T) {
+	if got := medianOf([]float64{1, 2, 3}); got != 2 {
+		t.Errorf("median of odd-length slice = %v, want 2", got)
+	}
+	if got := medianOf([]float64{1, 2, 3, 4}); got != 2.5 {
+		t.Errorf("median of even-length slice = %v, want 2.5", got)
+	}
+
+	// medianOf must not reorder the caller's slice.
+	vals := []float64{3, 1, 2}
+	medianOf(vals)
+	if vals[0] != 3 || vals[1] != 1 || vals[2] != 2 {
+		t.Errorf("medianOf mutated its input: %v", vals)
+	}
+}
+
+func TestDctAlpha(t *testing.T) {
+	const n = 8
+	if got := dctAlpha(0, n); math.Abs(got-math.Sqrt(1.0/n)) > 1e-9 {
+		t.Errorf("dctAlpha(0, %d) = %v, want sqrt(1/n)", n, got)
+	}
+	if got := dctAlpha(1, n); math.Abs(got-math.Sqrt(2.0/n)) > 1e-9 {
+		t.Errorf("dctAlpha(1, %d) = %v, want sqrt(2/n)", n, got)
+	}
+}
+
+// TestDCT1DConstantSignal checks that a DCT-II of a constant signal carries
+// all its energy in the DC (k=0) term, with every other coefficient at zero
+// -- the textbook sanity check for a DCT-II implementation.
+func TestDCT1DConstantSignal(t *testing.T) {
+	const n = 8
+	in := make([]float64, n)
+	for i := range in {
+		in[i] = 5
+	}
+
+	out := dct1D(in, n)
+
+	wantDC := 5 * float64(n) * math.Sqrt(1.0/n)
+	if math.Abs(out[0]-wantDC) > 1e-9 {
+		t.Errorf("DC term = %v, want %v", out[0], wantDC)
+	}
+	for k := 1; k < n; k++ {
+		if math.Abs(out[k]) > 1e-9 {
+			t.Errorf("coefficient %d = %v, want ~0 for a constant signal", k, out[k])
+		}
+	}
+}
+
+// TestDownscaleGraySolidColor checks that a uniformly-colored image
+// downscales to a matrix of the same luminance everywhere.
+func TestDownscaleGraySolidColor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	fill := color.RGBA{R: 100, G: 100, B: 100, A: 255}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+
+	out := downscaleGray(img, phashSize)
+
+	r, g, b, _ := fill.RGBA()
+	want := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+
+	for y := 0; y < phashSize; y++ {
+		for x := 0; x < phashSize; x++ {
+			if math.Abs(out[y][x]-want) > 1e-6 {
+				t.Fatalf("downscaleGray[%d][%d] = %v, want %v", y, x, out[y][x], want)
+			}
+		}
+	}
+}
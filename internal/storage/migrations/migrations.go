@@ -0,0 +1,120 @@
+// Package migrations embeds the versioned schema SQL files and parses them
+// into ordered Migration steps, one set per SQL dialect. It has no database
+// handle of its own; applying migrations is Database.Migrate's job.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+// Migration is a single versioned schema step, parsed from an
+// "NNNN_name.sql" file containing a "-- +migrate Up" section and an
+// optional "-- +migrate Down" section.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load returns the migrations for the given dialect ("postgres" or
+// "sqlite"), sorted by version.
+func Load(dialectName string) ([]Migration, error) {
+	var fsys embed.FS
+	switch dialectName {
+	case "postgres":
+		fsys = postgresFiles
+	case "sqlite":
+		fsys = sqliteFiles
+	default:
+		return nil, fmt.Errorf("migrations: no migrations embedded for dialect %q", dialectName)
+	}
+
+	entries, err := fsys.ReadDir(dialectName)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: failed to read %s migrations: %w", dialectName, err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		contents, err := fsys.ReadFile(path.Join(dialectName, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migrations: failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, err := parse(entry.Name(), string(contents))
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %w", err)
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parse splits a migration file's contents on the +migrate markers and
+// extracts the version from its "NNNN_name.sql" filename.
+func parse(filename, contents string) (Migration, error) {
+	version, name, err := parseFilename(filename)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	upIdx := strings.Index(contents, upMarker)
+	if upIdx == -1 {
+		return Migration{}, fmt.Errorf("%s: missing %q marker", filename, upMarker)
+	}
+	body := contents[upIdx+len(upMarker):]
+
+	up := body
+	down := ""
+	if downIdx := strings.Index(body, downMarker); downIdx != -1 {
+		up = body[:downIdx]
+		down = strings.TrimSpace(body[downIdx+len(downMarker):])
+	}
+
+	return Migration{
+		Version: version,
+		Name:    name,
+		Up:      strings.TrimSpace(up),
+		Down:    down,
+	}, nil
+}
+
+// parseFilename extracts the numeric version prefix and the descriptive
+// name from an "NNNN_name.sql" filename.
+func parseFilename(filename string) (version int64, name string, err error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	underscore := strings.Index(base, "_")
+	if underscore == -1 {
+		return 0, "", fmt.Errorf("%s: expected \"NNNN_name.sql\" filename", filename)
+	}
+
+	version, err = strconv.ParseInt(base[:underscore], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: invalid version prefix: %w", filename, err)
+	}
+
+	return version, base[underscore+1:], nil
+}
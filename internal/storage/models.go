@@ -13,14 +13,26 @@ type Camera struct {
 	Password  string    `db:"password" json:"-"` // Don't expose password in JSON
 	Status    string    `db:"status" json:"status"`
 	LastPing  *time.Time `db:"last_ping" json:"last_ping"`
+	// Latitude/Longitude/Timezone locate the camera so frames it captures
+	// can inherit a geo/time cell at insert time (see CellID on Frame).
+	Latitude  *float64  `db:"latitude" json:"latitude"`
+	Longitude *float64  `db:"longitude" json:"longitude"`
+	Timezone  string    `db:"timezone" json:"timezone"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
+// HasLocation reports whether the camera has a known lat/lon.
+func (c *Camera) HasLocation() bool {
+	return c.Latitude != nil && c.Longitude != nil
+}
+
 // Recording represents a video recording
 type Recording struct {
 	ID          int        `db:"id" json:"id"`
 	CameraID    int        `db:"camera_id" json:"camera_id"`
+	EventID     *int       `db:"event_id" json:"event_id"` // set for pre-event clips; nil for ordinary continuous recordings
+	ClipType    string     `db:"clip_type" json:"clip_type"` // "continuous" or "event"
 	FilePath    string     `db:"file_path" json:"file_path"`
 	FileSize    int64      `db:"file_size" json:"file_size"`
 	Duration    int        `db:"duration" json:"duration"` // seconds
@@ -46,7 +58,19 @@ type Frame struct {
 	Timestamp     time.Time  `db:"timestamp" json:"timestamp"`
 	HasDetection  bool       `db:"has_detection" json:"has_detection"`
 	Processed     bool       `db:"processed" json:"processed"`
-	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	// CellID is a geohash-style cell (see internal/geo) derived from the
+	// owning camera's location at insert time, and TakenAtLocal is
+	// Timestamp converted to the camera's timezone. Both are empty/zero for
+	// cameras without a known location. Together they let GetFrameClusters
+	// and GetDetectionsByCell answer map/timeline queries without scanning
+	// every frame row.
+	CellID       string    `db:"cell_id" json:"cell_id,omitempty"`
+	TakenAtLocal time.Time `db:"taken_at_local" json:"taken_at_local"`
+	// PHash is the frame's 64-bit perceptual hash (see frameHasher), used by
+	// SaveFrame to reject near-duplicate frames and by FindSimilar for
+	// similarity search. Nil for frames saved before this column existed.
+	PHash     *int64    `db:"phash" json:"phash,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
 // Detection represents object detection result
@@ -56,6 +80,9 @@ type Detection struct {
 	ObjectType  string  `db:"object_type" json:"object_type"`   // person, car, cat, etc
 	Confidence  float64 `db:"confidence" json:"confidence"`     // 0.0-1.0
 	BoundingBox string  `db:"bounding_box" json:"bounding_box"` // JSON: {x,y,w,h}
+	// CellID is copied from the parent Frame so detections can be queried
+	// by geo cell without a join.
+	CellID      string    `db:"cell_id" json:"cell_id,omitempty"`
 	Timestamp   time.Time `db:"timestamp" json:"timestamp"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
@@ -88,18 +115,52 @@ type SystemStats struct {
 	CreatedAt        time.Time `db:"created_at" json:"created_at"`
 }
 
+// User represents an account that can authenticate against the API.
+type User struct {
+	ID           int       `db:"id" json:"id"`
+	Username     string    `db:"username" json:"username"`
+	PasswordHash string    `db:"password_hash" json:"-"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// CameraACL grants a user a role on a specific camera. A user with no
+// CameraACL row for a camera has no access to it at all.
+type CameraACL struct {
+	UserID   int    `db:"user_id" json:"user_id"`
+	CameraID int    `db:"camera_id" json:"camera_id"`
+	Role     string `db:"role" json:"role"`
+}
+
+// Broadcast represents one egress pipeline re-publishing a camera's stream
+// to an external target (RTMP, HLS, or WebRTC).
+type Broadcast struct {
+	ID        int        `db:"id" json:"id"`
+	CameraID  int        `db:"camera_id" json:"camera_id"`
+	Protocol  string     `db:"protocol" json:"protocol"` // rtmp, hls, webrtc
+	TargetURL string     `db:"target_url" json:"target_url"`
+	Status    string     `db:"status" json:"status"` // active, stopped, error
+	StartedAt time.Time  `db:"started_at" json:"started_at"`
+	StoppedAt *time.Time `db:"stopped_at" json:"stopped_at"`
+	BytesSent int64      `db:"bytes_sent" json:"bytes_sent"`
+}
+
 // Constants for enum values
 const (
 	// Camera status
-	CameraStatusActive   = "active"
-	CameraStatusInactive = "inactive"
-	CameraStatusError    = "error"
+	CameraStatusActive     = "active"
+	CameraStatusInactive   = "inactive"
+	CameraStatusError      = "error"
+	CameraStatusDiscovered = "discovered"
 
 	// Recording status
 	RecordingStatusRecording = "recording"
 	RecordingStatusCompleted = "completed"
 	RecordingStatusFailed    = "failed"
 
+	// Recording clip types
+	ClipTypeContinuous = "continuous"
+	ClipTypeEvent      = "event"
+
 	// Event types
 	EventTypeMotion         = "motion"
 	EventTypePersonDetected = "person_detected"
@@ -109,6 +170,11 @@ const (
 	EventTypeCameraOffline  = "camera_offline"
 	EventTypeCameraOnline   = "camera_online"
 	EventTypeStorageFull    = "storage_full"
+	EventTypeBroadcastStarted = "broadcast_started"
+	EventTypeBroadcastError   = "broadcast_error"
+	EventTypeWebRTCConnected  = "webrtc_connected"
+	EventTypeWebRTCClosed     = "webrtc_closed"
+	EventTypeRetentionSweep   = "retention_sweep"
 
 	// Event severity
 	SeverityLow      = "low"
@@ -123,6 +189,21 @@ const (
 	ObjectTypeDog     = "dog"
 	ObjectTypeBird    = "bird"
 	ObjectTypePackage = "package"
+
+	// Camera ACL roles
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+
+	// Broadcast protocols
+	BroadcastProtocolRTMP   = "rtmp"
+	BroadcastProtocolHLS    = "hls"
+	BroadcastProtocolWebRTC = "webrtc"
+
+	// Broadcast status
+	BroadcastStatusActive  = "active"
+	BroadcastStatusStopped = "stopped"
+	BroadcastStatusError   = "error"
 )
 
 // Helper methods for models
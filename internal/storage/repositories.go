@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -26,6 +27,26 @@ type EventRepository struct {
 	db *Database
 }
 
+// UserRepository handles user account data operations
+type UserRepository struct {
+	db *Database
+}
+
+// CameraACLRepository handles per-camera access grants
+type CameraACLRepository struct {
+	db *Database
+}
+
+// SystemStatsRepository handles daily aggregated system statistics
+type SystemStatsRepository struct {
+	db *Database
+}
+
+// BroadcastRepository handles egress pipeline (RTMP/HLS/WebRTC) records
+type BroadcastRepository struct {
+	db *Database
+}
+
 // NewCameraRepository creates a new camera repository
 func NewCameraRepository(db *Database) *CameraRepository {
 	return &CameraRepository{db: db}
@@ -46,28 +67,59 @@ func NewEventRepository(db *Database) *EventRepository {
 	return &EventRepository{db: db}
 }
 
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *Database) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// NewCameraACLRepository creates a new camera ACL repository
+func NewCameraACLRepository(db *Database) *CameraACLRepository {
+	return &CameraACLRepository{db: db}
+}
+
+// NewSystemStatsRepository creates a new system stats repository
+func NewSystemStatsRepository(db *Database) *SystemStatsRepository {
+	return &SystemStatsRepository{db: db}
+}
+
+// NewBroadcastRepository creates a new broadcast repository
+func NewBroadcastRepository(db *Database) *BroadcastRepository {
+	return &BroadcastRepository{db: db}
+}
+
 // Camera Repository Methods
 
 // CreateCamera creates a new camera record
 func (r *CameraRepository) CreateCamera(camera *Camera) error {
+	now := time.Now()
+	camera.CreatedAt = now
+	camera.UpdatedAt = now
+
 	query := `
-		INSERT INTO cameras (name, rtsp_url, username, password, status)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at`
+		INSERT INTO cameras (name, rtsp_url, username, password, status, latitude, longitude, timezone, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	return r.db.conn.QueryRow(query, camera.Name, camera.RTSPURL, camera.Username,
-		camera.Password, camera.Status).Scan(&camera.ID, &camera.CreatedAt, &camera.UpdatedAt)
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, camera.Name, camera.RTSPURL,
+		camera.Username, camera.Password, camera.Status, camera.Latitude, camera.Longitude,
+		camera.Timezone, camera.CreatedAt, camera.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	camera.ID = int(id)
+	return nil
 }
 
 // GetCamera retrieves a camera by ID
 func (r *CameraRepository) GetCamera(id int) (*Camera, error) {
 	camera := &Camera{}
-	query := `
-		SELECT id, name, rtsp_url, username, password, status, last_ping, created_at, updated_at
-		FROM cameras WHERE id = $1`
+	query := r.db.dialect.Rewrite(`
+		SELECT id, name, rtsp_url, username, password, status, last_ping,
+			   latitude, longitude, COALESCE(timezone, ''), created_at, updated_at
+		FROM cameras WHERE id = ?`)
 
 	err := r.db.conn.QueryRow(query, id).Scan(&camera.ID, &camera.Name, &camera.RTSPURL,
 		&camera.Username, &camera.Password, &camera.Status, &camera.LastPing,
+		&camera.Latitude, &camera.Longitude, &camera.Timezone,
 		&camera.CreatedAt, &camera.UpdatedAt)
 
 	if err == sql.ErrNoRows {
@@ -76,21 +128,56 @@ func (r *CameraRepository) GetCamera(id int) (*Camera, error) {
 	return camera, err
 }
 
+// GetCameraByName retrieves a camera by its name, the stable key
+// syncCameras upserts by. Returns sql.ErrNoRows, unwrapped, so callers can
+// distinguish "no camera with this name yet" from a real lookup failure.
+func (r *CameraRepository) GetCameraByName(name string) (*Camera, error) {
+	camera := &Camera{}
+	query := r.db.dialect.Rewrite(`
+		SELECT id, name, rtsp_url, username, password, status, last_ping,
+			   latitude, longitude, COALESCE(timezone, ''), created_at, updated_at
+		FROM cameras WHERE name = ?`)
+
+	err := r.db.conn.QueryRow(query, name).Scan(&camera.ID, &camera.Name, &camera.RTSPURL,
+		&camera.Username, &camera.Password, &camera.Status, &camera.LastPing,
+		&camera.Latitude, &camera.Longitude, &camera.Timezone,
+		&camera.CreatedAt, &camera.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return camera, nil
+}
+
+// UpdateCameraConnection updates a camera's RTSP URL and credentials, e.g.
+// when syncCameras picks up an edited config.yaml entry for a camera that
+// already has a row.
+func (r *CameraRepository) UpdateCameraConnection(id int, rtspURL, username, password string) error {
+	query := r.db.dialect.Rewrite(`
+		UPDATE cameras
+		SET rtsp_url = ?, username = ?, password = ?, updated_at = ?
+		WHERE id = ?`)
+
+	_, err := r.db.conn.Exec(query, rtspURL, username, password, time.Now(), id)
+	return err
+}
+
 // UpdateCameraStatus updates camera status and last ping
 func (r *CameraRepository) UpdateCameraStatus(id int, status string) error {
-	query := `
-		UPDATE cameras 
-		SET status = $1, last_ping = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2`
+	now := time.Now()
+	query := r.db.dialect.Rewrite(`
+		UPDATE cameras
+		SET status = ?, last_ping = ?, updated_at = ?
+		WHERE id = ?`)
 
-	_, err := r.db.conn.Exec(query, status, id)
+	_, err := r.db.conn.Exec(query, status, now, now, id)
 	return err
 }
 
 // GetAllCameras retrieves all cameras
 func (r *CameraRepository) GetAllCameras() ([]Camera, error) {
 	query := `
-		SELECT id, name, rtsp_url, username, password, status, last_ping, created_at, updated_at
+		SELECT id, name, rtsp_url, username, password, status, last_ping,
+			   latitude, longitude, COALESCE(timezone, ''), created_at, updated_at
 		FROM cameras ORDER BY created_at`
 
 	rows, err := r.db.conn.Query(query)
@@ -103,7 +190,9 @@ func (r *CameraRepository) GetAllCameras() ([]Camera, error) {
 	for rows.Next() {
 		var camera Camera
 		err := rows.Scan(&camera.ID, &camera.Name, &camera.RTSPURL, &camera.Username,
-			&camera.Password, &camera.Status, &camera.LastPing, &camera.CreatedAt, &camera.UpdatedAt)
+			&camera.Password, &camera.Status, &camera.LastPing,
+			&camera.Latitude, &camera.Longitude, &camera.Timezone,
+			&camera.CreatedAt, &camera.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -117,22 +206,32 @@ func (r *CameraRepository) GetAllCameras() ([]Camera, error) {
 
 // CreateRecording creates a new recording record
 func (r *RecordingRepository) CreateRecording(recording *Recording) error {
+	recording.CreatedAt = time.Now()
+	if recording.ClipType == "" {
+		recording.ClipType = ClipTypeContinuous
+	}
+
 	query := `
-		INSERT INTO recordings (camera_id, file_path, start_time, quality, codec, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at`
+		INSERT INTO recordings (camera_id, event_id, clip_type, file_path, file_size, duration, start_time, end_time, quality, codec, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	return r.db.conn.QueryRow(query, recording.CameraID, recording.FilePath,
-		recording.StartTime, recording.Quality, recording.Codec, recording.Status).
-		Scan(&recording.ID, &recording.CreatedAt)
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, recording.CameraID,
+		recording.EventID, recording.ClipType, recording.FilePath, recording.FileSize,
+		recording.Duration, recording.StartTime, recording.EndTime, recording.Quality,
+		recording.Codec, recording.Status, recording.CreatedAt)
+	if err != nil {
+		return err
+	}
+	recording.ID = int(id)
+	return nil
 }
 
 // UpdateRecording updates recording information
 func (r *RecordingRepository) UpdateRecording(recording *Recording) error {
-	query := `
-		UPDATE recordings 
-		SET file_size = $1, duration = $2, end_time = $3, status = $4
-		WHERE id = $5`
+	query := r.db.dialect.Rewrite(`
+		UPDATE recordings
+		SET file_size = ?, duration = ?, end_time = ?, status = ?
+		WHERE id = ?`)
 
 	_, err := r.db.conn.Exec(query, recording.FileSize, recording.Duration,
 		recording.EndTime, recording.Status, recording.ID)
@@ -142,15 +241,15 @@ func (r *RecordingRepository) UpdateRecording(recording *Recording) error {
 // GetRecording retrieves a recording by ID
 func (r *RecordingRepository) GetRecording(id int) (*Recording, error) {
 	recording := &Recording{}
-	query := `
-		SELECT id, camera_id, file_path, file_size, duration, start_time, end_time,
+	query := r.db.dialect.Rewrite(`
+		SELECT id, camera_id, event_id, clip_type, file_path, file_size, duration, start_time, end_time,
 			   quality, codec, status, created_at, archived_at
-		FROM recordings WHERE id = $1`
+		FROM recordings WHERE id = ?`)
 
 	err := r.db.conn.QueryRow(query, id).Scan(&recording.ID, &recording.CameraID,
-		&recording.FilePath, &recording.FileSize, &recording.Duration, &recording.StartTime,
-		&recording.EndTime, &recording.Quality, &recording.Codec, &recording.Status,
-		&recording.CreatedAt, &recording.ArchivedAt)
+		&recording.EventID, &recording.ClipType, &recording.FilePath, &recording.FileSize,
+		&recording.Duration, &recording.StartTime, &recording.EndTime, &recording.Quality,
+		&recording.Codec, &recording.Status, &recording.CreatedAt, &recording.ArchivedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("recording not found")
@@ -160,13 +259,13 @@ func (r *RecordingRepository) GetRecording(id int) (*Recording, error) {
 
 // GetRecordingsByCamera retrieves recordings for a specific camera
 func (r *RecordingRepository) GetRecordingsByCamera(cameraID int, limit int) ([]Recording, error) {
-	query := `
-		SELECT id, camera_id, file_path, file_size, duration, start_time, end_time,
+	query := r.db.dialect.Rewrite(`
+		SELECT id, camera_id, event_id, clip_type, file_path, file_size, duration, start_time, end_time,
 			   quality, codec, status, created_at, archived_at
-		FROM recordings 
-		WHERE camera_id = $1 
-		ORDER BY start_time DESC 
-		LIMIT $2`
+		FROM recordings
+		WHERE camera_id = ?
+		ORDER BY start_time DESC
+		LIMIT ?`)
 
 	rows, err := r.db.conn.Query(query, cameraID, limit)
 	if err != nil {
@@ -177,10 +276,46 @@ func (r *RecordingRepository) GetRecordingsByCamera(cameraID int, limit int) ([]
 	var recordings []Recording
 	for rows.Next() {
 		var recording Recording
-		err := rows.Scan(&recording.ID, &recording.CameraID, &recording.FilePath,
-			&recording.FileSize, &recording.Duration, &recording.StartTime, &recording.EndTime,
-			&recording.Quality, &recording.Codec, &recording.Status, &recording.CreatedAt,
-			&recording.ArchivedAt)
+		err := rows.Scan(&recording.ID, &recording.CameraID, &recording.EventID,
+			&recording.ClipType, &recording.FilePath, &recording.FileSize, &recording.Duration,
+			&recording.StartTime, &recording.EndTime, &recording.Quality, &recording.Codec,
+			&recording.Status, &recording.CreatedAt, &recording.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+		recordings = append(recordings, recording)
+	}
+
+	return recordings, rows.Err()
+}
+
+// protectedRecordingClause excludes recordings linked to an event at or
+// above a severity rank within a keep-events grace window. Both
+// ListRecordingsForRetention and ListRecordingsExcludingProtected share it so a
+// recording protected by its event never gets swept by either the
+// age-based or size-based pass.
+const protectedRecordingClause = `
+	NOT (
+		e.id IS NOT NULL
+		AND e.timestamp >= ?
+		AND (CASE e.severity
+			WHEN 'critical' THEN 3
+			WHEN 'high' THEN 2
+			WHEN 'medium' THEN 1
+			ELSE 0
+		END) >= ?
+	)`
+
+func scanRetentionRecordings(rows *sql.Rows) ([]Recording, error) {
+	defer rows.Close()
+
+	var recordings []Recording
+	for rows.Next() {
+		var recording Recording
+		err := rows.Scan(&recording.ID, &recording.CameraID, &recording.EventID,
+			&recording.ClipType, &recording.FilePath, &recording.FileSize, &recording.Duration,
+			&recording.StartTime, &recording.EndTime, &recording.Quality, &recording.Codec,
+			&recording.Status, &recording.CreatedAt, &recording.ArchivedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -190,26 +325,159 @@ func (r *RecordingRepository) GetRecordingsByCamera(cameraID int, limit int) ([]
 	return recordings, rows.Err()
 }
 
+// ListRecordingsForRetention returns recordings that started before
+// cutoff, oldest first, excluding any still protected by
+// protectedRecordingClause. limit bounds how many a single retention
+// sweep processes at once.
+func (r *RecordingRepository) ListRecordingsForRetention(cutoff, protectedSince time.Time, minSeverityRank, limit int) ([]Recording, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT r.id, r.camera_id, r.event_id, r.clip_type, r.file_path, r.file_size, r.duration,
+			   r.start_time, r.end_time, r.quality, r.codec, r.status, r.created_at, r.archived_at
+		FROM recordings r
+		LEFT JOIN events e ON r.event_id = e.id
+		WHERE r.start_time < ?
+		  AND ` + protectedRecordingClause + `
+		ORDER BY r.start_time ASC
+		LIMIT ?`)
+
+	rows, err := r.db.conn.Query(query, cutoff, protectedSince, minSeverityRank, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionRecordings(rows)
+}
+
+// ListRecordingsExcludingProtected returns every unprotected recording,
+// oldest first, for the size-cap retention pass to delete from until
+// total usage is back under the configured cap.
+func (r *RecordingRepository) ListRecordingsExcludingProtected(protectedSince time.Time, minSeverityRank, limit int) ([]Recording, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT r.id, r.camera_id, r.event_id, r.clip_type, r.file_path, r.file_size, r.duration,
+			   r.start_time, r.end_time, r.quality, r.codec, r.status, r.created_at, r.archived_at
+		FROM recordings r
+		LEFT JOIN events e ON r.event_id = e.id
+		WHERE ` + protectedRecordingClause + `
+		ORDER BY r.start_time ASC
+		LIMIT ?`)
+
+	rows, err := r.db.conn.Query(query, protectedSince, minSeverityRank, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionRecordings(rows)
+}
+
+// DeleteRecording removes a recording row; its frames (and their
+// detections) cascade via the DB's foreign key.
+func (r *RecordingRepository) DeleteRecording(id int) error {
+	query := r.db.dialect.Rewrite(`DELETE FROM recordings WHERE id = ?`)
+	_, err := r.db.conn.Exec(query, id)
+	return err
+}
+
 // Frame Repository Methods
 
 // CreateFrame creates a new frame record
 func (r *FrameRepository) CreateFrame(frame *Frame) error {
+	frame.CreatedAt = time.Now()
+
 	query := `
-		INSERT INTO frames (recording_id, camera_id, file_path, file_size, width, height, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at`
+		INSERT INTO frames (recording_id, camera_id, file_path, file_size, width, height,
+							 timestamp, cell_id, taken_at_local, phash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, frame.RecordingID, frame.CameraID,
+		frame.FilePath, frame.FileSize, frame.Width, frame.Height, frame.Timestamp,
+		frame.CellID, frame.TakenAtLocal, frame.PHash, frame.CreatedAt)
+	if err != nil {
+		return err
+	}
+	frame.ID = int(id)
+	return nil
+}
+
+// CreateFramesBatch inserts multiple frames with a single multi-row
+// INSERT ... VALUES (...),(...) statement, assigning each frame's ID in
+// place in the same order they were passed in. Used by frameBatcher to
+// collapse concurrent SaveFrame calls into one round trip.
+func (r *FrameRepository) CreateFramesBatch(frames []*Frame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO frames (recording_id, camera_id, file_path, file_size, width, height,
+						 timestamp, cell_id, taken_at_local, phash, created_at) VALUES `)
+
+	args := make([]interface{}, 0, len(frames)*11)
+	for i, frame := range frames {
+		frame.CreatedAt = now
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, frame.RecordingID, frame.CameraID, frame.FilePath, frame.FileSize,
+			frame.Width, frame.Height, frame.Timestamp, frame.CellID, frame.TakenAtLocal, frame.PHash, frame.CreatedAt)
+	}
+
+	ids, err := r.db.dialect.InsertReturningIDs(r.db.conn, sb.String(), len(frames), args...)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		frames[i].ID = int(id)
+	}
+	return nil
+}
+
+// FrameProcessedUpdate is one frame's worth of detection results, as applied
+// by UpdateFramesBatch.
+type FrameProcessedUpdate struct {
+	FrameID       int
+	HasDetection  bool
+	Processed     bool
+	ThumbnailPath *string
+}
+
+// UpdateFramesBatch applies multiple detection-result updates with a single
+// UPDATE ... FROM (VALUES ...) statement instead of one UPDATE per frame.
+// Used by frameBatcher to collapse concurrent UpdateFrameProcessed calls.
+func (r *FrameRepository) UpdateFramesBatch(updates []FrameProcessedUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`UPDATE frames AS f SET
+						has_detection = v.column2,
+						processed = v.column3,
+						thumbnail_path = v.column4
+					FROM (VALUES `)
+
+	args := make([]interface{}, 0, len(updates)*4)
+	for i, u := range updates {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("(CAST(? AS INTEGER), CAST(? AS BOOLEAN), CAST(? AS BOOLEAN), CAST(? AS TEXT))")
+		args = append(args, u.FrameID, u.HasDetection, u.Processed, u.ThumbnailPath)
+	}
+	// SQLite doesn't accept a column list on the VALUES alias, so refer to
+	// the default column1..column4 names both dialects give VALUES columns.
+	sb.WriteString(`) AS v
+					WHERE f.id = v.column1`)
 
-	return r.db.conn.QueryRow(query, frame.RecordingID, frame.CameraID, frame.FilePath,
-		frame.FileSize, frame.Width, frame.Height, frame.Timestamp).
-		Scan(&frame.ID, &frame.CreatedAt)
+	_, err := r.db.conn.Exec(r.db.dialect.Rewrite(sb.String()), args...)
+	return err
 }
 
 // UpdateFrame updates frame information
 func (r *FrameRepository) UpdateFrame(frame *Frame) error {
-	query := `
-		UPDATE frames 
-		SET thumbnail_path = $1, has_detection = $2, processed = $3
-		WHERE id = $4`
+	query := r.db.dialect.Rewrite(`
+		UPDATE frames
+		SET thumbnail_path = ?, has_detection = ?, processed = ?
+		WHERE id = ?`)
 
 	_, err := r.db.conn.Exec(query, frame.ThumbnailPath, frame.HasDetection,
 		frame.Processed, frame.ID)
@@ -219,15 +487,16 @@ func (r *FrameRepository) UpdateFrame(frame *Frame) error {
 // GetFrame retrieves a frame by ID
 func (r *FrameRepository) GetFrame(id int) (*Frame, error) {
 	frame := &Frame{}
-	query := `
+	query := r.db.dialect.Rewrite(`
 		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
-			   width, height, timestamp, has_detection, processed, created_at
-		FROM frames WHERE id = $1`
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, created_at
+		FROM frames WHERE id = ?`)
 
 	err := r.db.conn.QueryRow(query, id).Scan(&frame.ID, &frame.RecordingID,
 		&frame.CameraID, &frame.FilePath, &frame.ThumbnailPath, &frame.FileSize,
 		&frame.Width, &frame.Height, &frame.Timestamp, &frame.HasDetection,
-		&frame.Processed, &frame.CreatedAt)
+		&frame.Processed, &frame.CellID, &frame.TakenAtLocal, &frame.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("frame not found")
@@ -235,15 +504,74 @@ func (r *FrameRepository) GetFrame(id int) (*Frame, error) {
 	return frame, err
 }
 
+// GetLastPHash returns the most recently saved frame's pHash for cameraID,
+// or nil if the camera has no frames yet, or its latest frame predates the
+// phash column. Used by Service.SaveFrame to decide whether an incoming
+// frame is a near-duplicate of the last one.
+func (r *FrameRepository) GetLastPHash(cameraID int) (*int64, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT phash FROM frames
+		WHERE camera_id = ?
+		ORDER BY timestamp DESC
+		LIMIT 1`)
+
+	var phash *int64
+	err := r.db.conn.QueryRow(query, cameraID).Scan(&phash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return phash, nil
+}
+
+// FindSimilar returns every frame for cameraID whose pHash is within
+// maxDist Hamming bits of phash, most recent first. Frames saved before
+// the phash column existed are skipped. Distance is computed in Go rather
+// than in SQL since bitwise popcount isn't portable across the postgres/
+// sqlite dialects this package supports.
+func (r *FrameRepository) FindSimilar(cameraID int, phash int64, maxDist int) ([]Frame, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, phash, created_at
+		FROM frames
+		WHERE camera_id = ? AND phash IS NOT NULL
+		ORDER BY timestamp DESC`)
+
+	rows, err := r.db.conn.Query(query, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []Frame
+	for rows.Next() {
+		var frame Frame
+		if err := rows.Scan(&frame.ID, &frame.RecordingID, &frame.CameraID, &frame.FilePath,
+			&frame.ThumbnailPath, &frame.FileSize, &frame.Width, &frame.Height,
+			&frame.Timestamp, &frame.HasDetection, &frame.Processed,
+			&frame.CellID, &frame.TakenAtLocal, &frame.PHash, &frame.CreatedAt); err != nil {
+			return nil, err
+		}
+		if frame.PHash != nil && hammingDistance(*frame.PHash, phash) <= maxDist {
+			matches = append(matches, frame)
+		}
+	}
+	return matches, rows.Err()
+}
+
 // GetFramesByTimeRange retrieves frames within a time range
 func (r *FrameRepository) GetFramesByTimeRange(cameraID int, start, end time.Time, limit int) ([]Frame, error) {
-	query := `
+	query := r.db.dialect.Rewrite(`
 		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
-			   width, height, timestamp, has_detection, processed, created_at
-		FROM frames 
-		WHERE camera_id = $1 AND timestamp BETWEEN $2 AND $3
-		ORDER BY timestamp DESC 
-		LIMIT $4`
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, created_at
+		FROM frames
+		WHERE camera_id = ? AND timestamp BETWEEN ? AND ?
+		ORDER BY timestamp DESC
+		LIMIT ?`)
 
 	rows, err := r.db.conn.Query(query, cameraID, start, end, limit)
 	if err != nil {
@@ -256,7 +584,8 @@ func (r *FrameRepository) GetFramesByTimeRange(cameraID int, start, end time.Tim
 		var frame Frame
 		err := rows.Scan(&frame.ID, &frame.RecordingID, &frame.CameraID, &frame.FilePath,
 			&frame.ThumbnailPath, &frame.FileSize, &frame.Width, &frame.Height,
-			&frame.Timestamp, &frame.HasDetection, &frame.Processed, &frame.CreatedAt)
+			&frame.Timestamp, &frame.HasDetection, &frame.Processed,
+			&frame.CellID, &frame.TakenAtLocal, &frame.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -268,13 +597,14 @@ func (r *FrameRepository) GetFramesByTimeRange(cameraID int, start, end time.Tim
 
 // GetUnprocessedFrames retrieves frames that haven't been processed yet
 func (r *FrameRepository) GetUnprocessedFrames(limit int) ([]Frame, error) {
-	query := `
+	query := r.db.dialect.Rewrite(`
 		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
-			   width, height, timestamp, has_detection, processed, created_at
-		FROM frames 
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, created_at
+		FROM frames
 		WHERE processed = FALSE
-		ORDER BY created_at ASC 
-		LIMIT $1`
+		ORDER BY created_at ASC
+		LIMIT ?`)
 
 	rows, err := r.db.conn.Query(query, limit)
 	if err != nil {
@@ -287,7 +617,27 @@ func (r *FrameRepository) GetUnprocessedFrames(limit int) ([]Frame, error) {
 		var frame Frame
 		err := rows.Scan(&frame.ID, &frame.RecordingID, &frame.CameraID, &frame.FilePath,
 			&frame.ThumbnailPath, &frame.FileSize, &frame.Width, &frame.Height,
-			&frame.Timestamp, &frame.HasDetection, &frame.Processed, &frame.CreatedAt)
+			&frame.Timestamp, &frame.HasDetection, &frame.Processed,
+			&frame.CellID, &frame.TakenAtLocal, &frame.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames, rows.Err()
+}
+
+func scanRetentionFrames(rows *sql.Rows) ([]Frame, error) {
+	defer rows.Close()
+
+	var frames []Frame
+	for rows.Next() {
+		var frame Frame
+		err := rows.Scan(&frame.ID, &frame.RecordingID, &frame.CameraID, &frame.FilePath,
+			&frame.ThumbnailPath, &frame.FileSize, &frame.Width, &frame.Height,
+			&frame.Timestamp, &frame.HasDetection, &frame.Processed,
+			&frame.CellID, &frame.TakenAtLocal, &frame.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -297,28 +647,184 @@ func (r *FrameRepository) GetUnprocessedFrames(limit int) ([]Frame, error) {
 	return frames, rows.Err()
 }
 
+// GetFramesByRecording returns every frame belonging to recordingID, so a
+// retention sweep can delete their backend blobs before the recording row
+// (and its cascaded frame rows) is deleted.
+func (r *FrameRepository) GetFramesByRecording(recordingID int) ([]Frame, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, created_at
+		FROM frames WHERE recording_id = ?`)
+
+	rows, err := r.db.conn.Query(query, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionFrames(rows)
+}
+
+// ListStandaloneFramesForRetention returns frames with no owning
+// recording (recording_id IS NULL) captured before cutoff, oldest first.
+// Such frames aren't covered by a recording's cascade delete, so
+// retention sweeps them directly.
+func (r *FrameRepository) ListStandaloneFramesForRetention(cutoff time.Time, limit int) ([]Frame, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, recording_id, camera_id, file_path, thumbnail_path, file_size,
+			   width, height, timestamp, has_detection, processed,
+			   COALESCE(cell_id, ''), taken_at_local, created_at
+		FROM frames
+		WHERE recording_id IS NULL AND timestamp < ?
+		ORDER BY timestamp ASC
+		LIMIT ?`)
+
+	rows, err := r.db.conn.Query(query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRetentionFrames(rows)
+}
+
+// DeleteFrame removes a single frame row (and its detections, which
+// cascade via the DB's foreign key).
+func (r *FrameRepository) DeleteFrame(id int) error {
+	query := r.db.dialect.Rewrite(`DELETE FROM frames WHERE id = ?`)
+	_, err := r.db.conn.Exec(query, id)
+	return err
+}
+
+// Cluster is an aggregated bucket of frames sharing a truncated geo cell
+// within a time window, as returned by GetFrameClusters for map heat-tiles
+// and per-camera activity timelines.
+type Cluster struct {
+	CellID        string    `json:"cell_id"`
+	Count         int       `json:"count"`
+	FirstTS       time.Time `json:"first_ts"`
+	LastTS        time.Time `json:"last_ts"`
+	SampleFrameID int       `json:"sample_frame_id"`
+}
+
+// GetFrameClusters groups frames captured between start and end by the
+// first cellPrefixLen characters of their cell_id, returning one Cluster per
+// distinct prefix. A single GROUP BY query backs this so the UI can draw
+// heat-map tiles and timelines without scanning every frame row.
+func (r *FrameRepository) GetFrameClusters(cellPrefixLen int, start, end time.Time) ([]Cluster, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT substr(cell_id, 1, ?) AS cell_prefix,
+			   COUNT(*) AS cnt,
+			   MIN(timestamp) AS first_ts,
+			   MAX(timestamp) AS last_ts,
+			   MAX(id) AS sample_frame_id
+		FROM frames
+		WHERE cell_id IS NOT NULL AND cell_id != '' AND timestamp BETWEEN ? AND ?
+		GROUP BY cell_prefix
+		ORDER BY cell_prefix`)
+
+	rows, err := r.db.conn.Query(query, cellPrefixLen, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clusters []Cluster
+	for rows.Next() {
+		var c Cluster
+		if err := rows.Scan(&c.CellID, &c.Count, &c.FirstTS, &c.LastTS, &c.SampleFrameID); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, c)
+	}
+
+	return clusters, rows.Err()
+}
+
+// DetectionRepository handles detection data operations
+type DetectionRepository struct {
+	db *Database
+}
+
+// NewDetectionRepository creates a new detection repository
+func NewDetectionRepository(db *Database) *DetectionRepository {
+	return &DetectionRepository{db: db}
+}
+
+// CreateDetection creates a new detection record, inheriting cell_id from
+// its parent frame so it can be queried by geo cell without a join.
+func (r *DetectionRepository) CreateDetection(detection *Detection) error {
+	detection.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO detections (frame_id, object_type, confidence, bounding_box, cell_id, timestamp, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, detection.FrameID,
+		detection.ObjectType, detection.Confidence, detection.BoundingBox, detection.CellID,
+		detection.Timestamp, detection.CreatedAt)
+	if err != nil {
+		return err
+	}
+	detection.ID = int(id)
+	return nil
+}
+
+// GetDetectionsByCell retrieves detections within a geo cell and time range.
+// cellID is matched as a prefix so callers can pass a truncated cell for a
+// coarser area, mirroring GetFrameClusters' cellPrefixLen.
+func (r *DetectionRepository) GetDetectionsByCell(cellID string, start, end time.Time) ([]Detection, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, frame_id, object_type, confidence, bounding_box,
+			   COALESCE(cell_id, ''), timestamp, created_at
+		FROM detections
+		WHERE cell_id LIKE ? AND timestamp BETWEEN ? AND ?
+		ORDER BY timestamp DESC`)
+
+	rows, err := r.db.conn.Query(query, cellID+"%", start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var detections []Detection
+	for rows.Next() {
+		var d Detection
+		err := rows.Scan(&d.ID, &d.FrameID, &d.ObjectType, &d.Confidence, &d.BoundingBox,
+			&d.CellID, &d.Timestamp, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		detections = append(detections, d)
+	}
+
+	return detections, rows.Err()
+}
+
 // Event Repository Methods
 
 // CreateEvent creates a new event record
 func (r *EventRepository) CreateEvent(event *Event) error {
+	event.CreatedAt = time.Now()
+
 	query := `
-		INSERT INTO events (camera_id, event_type, severity, title, message, metadata, timestamp)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at`
+		INSERT INTO events (camera_id, event_type, severity, title, message, metadata, timestamp, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 
-	return r.db.conn.QueryRow(query, event.CameraID, event.EventType, event.Severity,
-		event.Title, event.Message, event.Metadata, event.Timestamp).
-		Scan(&event.ID, &event.CreatedAt)
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, event.CameraID, event.EventType,
+		event.Severity, event.Title, event.Message, event.Metadata, event.Timestamp, event.CreatedAt)
+	if err != nil {
+		return err
+	}
+	event.ID = int(id)
+	return nil
 }
 
 // GetRecentEvents retrieves recent events
 func (r *EventRepository) GetRecentEvents(limit int) ([]Event, error) {
-	query := `
+	query := r.db.dialect.Rewrite(`
 		SELECT id, camera_id, event_type, severity, title, message, metadata,
 			   notified, resolved, timestamp, created_at, resolved_at
-		FROM events 
-		ORDER BY timestamp DESC 
-		LIMIT $1`
+		FROM events
+		ORDER BY timestamp DESC
+		LIMIT ?`)
 
 	rows, err := r.db.conn.Query(query, limit)
 	if err != nil {
@@ -346,7 +852,7 @@ func (r *EventRepository) GetUnnotifiedEvents() ([]Event, error) {
 	query := `
 		SELECT id, camera_id, event_type, severity, title, message, metadata,
 			   notified, resolved, timestamp, created_at, resolved_at
-		FROM events 
+		FROM events
 		WHERE notified = FALSE AND (severity = 'high' OR severity = 'critical')
 		ORDER BY timestamp ASC`
 
@@ -371,16 +877,250 @@ func (r *EventRepository) GetUnnotifiedEvents() ([]Event, error) {
 	return events, rows.Err()
 }
 
+// GetEventsAfter retrieves events with ID greater than afterID, ascending by
+// ID. Used to replay events a reconnecting SSE client missed while
+// disconnected before it switches over to live push.
+func (r *EventRepository) GetEventsAfter(afterID int, limit int) ([]Event, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, camera_id, event_type, severity, title, message, metadata,
+			   notified, resolved, timestamp, created_at, resolved_at
+		FROM events
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?`)
+
+	rows, err := r.db.conn.Query(query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		err := rows.Scan(&event.ID, &event.CameraID, &event.EventType, &event.Severity,
+			&event.Title, &event.Message, &event.Metadata, &event.Notified, &event.Resolved,
+			&event.Timestamp, &event.CreatedAt, &event.ResolvedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
 // MarkEventNotified marks an event as notified
 func (r *EventRepository) MarkEventNotified(id int) error {
-	query := `UPDATE events SET notified = TRUE WHERE id = $1`
+	query := r.db.dialect.Rewrite(`UPDATE events SET notified = TRUE WHERE id = ?`)
 	_, err := r.db.conn.Exec(query, id)
 	return err
 }
 
 // MarkEventResolved marks an event as resolved
 func (r *EventRepository) MarkEventResolved(id int) error {
-	query := `UPDATE events SET resolved = TRUE, resolved_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err := r.db.conn.Exec(query, id)
+	query := r.db.dialect.Rewrite(`UPDATE events SET resolved = TRUE, resolved_at = ? WHERE id = ?`)
+	_, err := r.db.conn.Exec(query, time.Now(), id)
+	return err
+}
+
+// User Repository Methods
+
+// CreateUser inserts a new user. Callers are responsible for hashing the
+// password before it reaches here.
+func (r *UserRepository) CreateUser(user *User) error {
+	user.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO users (username, password_hash, created_at)
+		VALUES (?, ?, ?)`
+
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, user.Username, user.PasswordHash, user.CreatedAt)
+	if err != nil {
+		return err
+	}
+	user.ID = int(id)
+	return nil
+}
+
+// GetUserByUsername retrieves a user by username
+func (r *UserRepository) GetUserByUsername(username string) (*User, error) {
+	user := &User{}
+	query := r.db.dialect.Rewrite(`
+		SELECT id, username, password_hash, created_at
+		FROM users WHERE username = ?`)
+
+	err := r.db.conn.QueryRow(query, username).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, err
+}
+
+// GetUserByID retrieves a user by ID
+func (r *UserRepository) GetUserByID(id int) (*User, error) {
+	user := &User{}
+	query := r.db.dialect.Rewrite(`
+		SELECT id, username, password_hash, created_at
+		FROM users WHERE id = ?`)
+
+	err := r.db.conn.QueryRow(query, id).Scan(&user.ID, &user.Username, &user.PasswordHash, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, err
+}
+
+// GetAllUsers retrieves all users
+func (r *UserRepository) GetAllUsers() ([]User, error) {
+	query := `SELECT id, username, password_hash, created_at FROM users ORDER BY created_at`
+
+	rows, err := r.db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// Camera ACL Repository Methods
+
+// GrantAccess upserts the role a user has on a camera.
+func (r *CameraACLRepository) GrantAccess(userID, cameraID int, role string) error {
+	query := r.db.dialect.Rewrite(`
+		INSERT INTO camera_acl (user_id, camera_id, role)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, camera_id) DO UPDATE SET role = excluded.role`)
+
+	_, err := r.db.conn.Exec(query, userID, cameraID, role)
+	return err
+}
+
+// GetRole returns the role a user has on a camera, or an error if the user
+// has no access to it.
+func (r *CameraACLRepository) GetRole(userID, cameraID int) (string, error) {
+	var role string
+	query := r.db.dialect.Rewrite(`SELECT role FROM camera_acl WHERE user_id = ? AND camera_id = ?`)
+
+	err := r.db.conn.QueryRow(query, userID, cameraID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("user has no access to camera %d", cameraID)
+	}
+	return role, err
+}
+
+// GetCameraIDsForUser returns the IDs of every camera the user has any role
+// on, for scoping camera/recording/frame/event queries to their ACL.
+func (r *CameraACLRepository) GetCameraIDsForUser(userID int) ([]int, error) {
+	query := r.db.dialect.Rewrite(`SELECT camera_id FROM camera_acl WHERE user_id = ?`)
+
+	rows, err := r.db.conn.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// System Stats Repository Methods
+
+// Upsert writes the daily aggregate for date, replacing any existing row
+// for that day.
+func (r *SystemStatsRepository) Upsert(date time.Time, totalRecordings, totalFrames, totalDetections int, storageUsedBytes int64, uptimeSeconds int) error {
+	query := r.db.dialect.Rewrite(`
+		INSERT INTO system_stats (date, total_recordings, total_frames, total_detections, storage_used_bytes, uptime_seconds, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (date) DO UPDATE SET
+			total_recordings = excluded.total_recordings,
+			total_frames = excluded.total_frames,
+			total_detections = excluded.total_detections,
+			storage_used_bytes = excluded.storage_used_bytes,
+			uptime_seconds = excluded.uptime_seconds`)
+
+	_, err := r.db.conn.Exec(query, date, totalRecordings, totalFrames, totalDetections,
+		storageUsedBytes, uptimeSeconds, time.Now())
+	return err
+}
+
+// Broadcast Repository Methods
+
+// CreateBroadcast records the start of a new egress pipeline
+func (r *BroadcastRepository) CreateBroadcast(broadcast *Broadcast) error {
+	query := `
+		INSERT INTO broadcasts (camera_id, protocol, target_url, status, started_at, bytes_sent)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	id, err := r.db.dialect.InsertReturningID(r.db.conn, query, broadcast.CameraID,
+		broadcast.Protocol, broadcast.TargetURL, broadcast.Status, broadcast.StartedAt, broadcast.BytesSent)
+	if err != nil {
+		return err
+	}
+	broadcast.ID = int(id)
+	return nil
+}
+
+// UpdateBroadcastStatus updates a broadcast's status and running byte
+// count, stamping stopped_at when the new status isn't active.
+func (r *BroadcastRepository) UpdateBroadcastStatus(id int, status string, bytesSent int64) error {
+	var stoppedAt *time.Time
+	if status != BroadcastStatusActive {
+		now := time.Now()
+		stoppedAt = &now
+	}
+
+	query := r.db.dialect.Rewrite(`
+		UPDATE broadcasts
+		SET status = ?, bytes_sent = ?, stopped_at = ?
+		WHERE id = ?`)
+
+	_, err := r.db.conn.Exec(query, status, bytesSent, stoppedAt, id)
 	return err
-}
\ No newline at end of file
+}
+
+// GetBroadcastsByCamera retrieves the most recent broadcasts for a camera
+func (r *BroadcastRepository) GetBroadcastsByCamera(cameraID int, limit int) ([]Broadcast, error) {
+	query := r.db.dialect.Rewrite(`
+		SELECT id, camera_id, protocol, target_url, status, started_at, stopped_at, bytes_sent
+		FROM broadcasts
+		WHERE camera_id = ?
+		ORDER BY started_at DESC
+		LIMIT ?`)
+
+	rows, err := r.db.conn.Query(query, cameraID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var broadcasts []Broadcast
+	for rows.Next() {
+		var b Broadcast
+		if err := rows.Scan(&b.ID, &b.CameraID, &b.Protocol, &b.TargetURL,
+			&b.Status, &b.StartedAt, &b.StoppedAt, &b.BytesSent); err != nil {
+			return nil, err
+		}
+		broadcasts = append(broadcasts, b)
+	}
+
+	return broadcasts, rows.Err()
+}
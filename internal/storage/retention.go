@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// retentionCheckInterval is how often runRetentionLoop sweeps for
+// recordings/frames RunRetention should delete.
+const retentionCheckInterval = 1 * time.Hour
+
+// retentionSweepBatchLimit bounds how many aged recordings (and, separately,
+// aged standalone frames) a single RunRetention sweep deletes, so one sweep
+// can't block the loop indefinitely on a backlog built up while retention
+// was disabled. Anything left over is picked up on the next tick. The
+// size-cap pass lists recordings in batches of this size but keeps going
+// until usage is under the cap or nothing unprotected is left.
+const retentionSweepBatchLimit = 500
+
+// severityRank orders event severities so RunRetention can compare a
+// recording's linked event against cfg.RetentionMinSeverity.
+var severityRank = map[string]int{
+	SeverityLow:      0,
+	SeverityMedium:   1,
+	SeverityHigh:     2,
+	SeverityCritical: 3,
+}
+
+// runRetentionLoop runs RunRetention on retentionCheckInterval until ctx
+// is cancelled. NewService starts this as a background goroutine; Close
+// cancels it via s.retentionCancel.
+func (s *Service) runRetentionLoop(ctx context.Context) {
+	ticker := time.NewTicker(retentionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunRetention(ctx); err != nil {
+				s.Logger.Warn("retention sweep failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// RunRetention performs one retention sweep: it deletes recordings (and
+// their frames) older than cfg.RetentionMaxAgeDays, deletes any
+// standalone frame (one with no owning recording) older than the same
+// cutoff, and then, if cfg.RetentionMaxSizeGB is set, deletes the oldest
+// remaining recordings until total backend usage is back under that cap.
+// A recording linked to an event at or above cfg.RetentionMinSeverity is
+// protected from both passes for cfg.RetentionKeepEventsDays after the
+// event fired. Every sweep that deletes anything emits an
+// EventTypeRetentionSweep system event summarizing counts and bytes
+// reclaimed.
+func (s *Service) RunRetention(ctx context.Context) error {
+	cfg := s.config
+	if cfg.RetentionMaxAgeDays <= 0 && cfg.RetentionMaxSizeGB <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	minRank := severityRank[cfg.RetentionMinSeverity]
+	protectedSince := now.Add(-time.Duration(cfg.RetentionKeepEventsDays) * 24 * time.Hour)
+
+	var recordingsDeleted, framesDeleted int
+	var bytesReclaimed int64
+
+	if cfg.RetentionMaxAgeDays > 0 {
+		ageCutoff := now.Add(-time.Duration(cfg.RetentionMaxAgeDays) * 24 * time.Hour)
+
+		recordings, err := s.recordingRepo.ListRecordingsForRetention(ageCutoff, protectedSince, minRank, retentionSweepBatchLimit)
+		if err != nil {
+			return fmt.Errorf("retention: failed to list aged recordings: %w", err)
+		}
+		n, f, b, err := s.deleteRecordings(recordings)
+		if err != nil {
+			return fmt.Errorf("retention: failed to delete aged recordings: %w", err)
+		}
+		recordingsDeleted += n
+		framesDeleted += f
+		bytesReclaimed += b
+
+		frames, err := s.frameRepo.ListStandaloneFramesForRetention(ageCutoff, retentionSweepBatchLimit)
+		if err != nil {
+			return fmt.Errorf("retention: failed to list aged standalone frames: %w", err)
+		}
+		f, b, err = s.deleteFrames(frames)
+		if err != nil {
+			return fmt.Errorf("retention: failed to delete aged standalone frames: %w", err)
+		}
+		framesDeleted += f
+		bytesReclaimed += b
+	}
+
+	if cfg.RetentionMaxSizeGB > 0 {
+		capBytes := cfg.RetentionMaxSizeGB * (1 << 30)
+		n, f, b, err := s.sweepOverCapacity(capBytes, protectedSince, minRank)
+		if err != nil {
+			return fmt.Errorf("retention: failed to sweep over capacity: %w", err)
+		}
+		recordingsDeleted += n
+		framesDeleted += f
+		bytesReclaimed += b
+	}
+
+	if recordingsDeleted == 0 && framesDeleted == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("Retention sweep deleted %d recording(s) and %d frame(s), reclaiming %d bytes",
+		recordingsDeleted, framesDeleted, bytesReclaimed)
+	if err := s.CreateSystemEvent(EventTypeRetentionSweep, SeverityLow, "Retention sweep completed", message); err != nil {
+		s.Logger.Warn("failed to record retention_sweep event", zap.Error(err))
+	}
+
+	s.Logger.Info("retention sweep complete",
+		zap.Int("recordings_deleted", recordingsDeleted),
+		zap.Int("frames_deleted", framesDeleted),
+		zap.Int64("bytes_reclaimed", bytesReclaimed))
+	return nil
+}
+
+// sweepOverCapacity deletes the oldest unprotected recordings, one batch
+// at a time, until backend usage is at or under capBytes or there's
+// nothing left to delete.
+func (s *Service) sweepOverCapacity(capBytes int64, protectedSince time.Time, minRank int) (int, int, int64, error) {
+	usage, err := s.backend.Usage()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to read backend usage: %w", err)
+	}
+	if usage <= capBytes {
+		return 0, 0, 0, nil
+	}
+
+	var recordingsDeleted, framesDeleted int
+	var bytesReclaimed int64
+
+	for usage > capBytes {
+		recordings, err := s.recordingRepo.ListRecordingsExcludingProtected(protectedSince, minRank, retentionSweepBatchLimit)
+		if err != nil {
+			return recordingsDeleted, framesDeleted, bytesReclaimed, err
+		}
+		if len(recordings) == 0 {
+			break
+		}
+
+		for _, recording := range recordings {
+			if usage <= capBytes {
+				break
+			}
+
+			n, f, b, err := s.deleteRecordings([]Recording{recording})
+			if err != nil {
+				return recordingsDeleted, framesDeleted, bytesReclaimed, err
+			}
+			recordingsDeleted += n
+			framesDeleted += f
+			bytesReclaimed += b
+			usage -= b
+		}
+	}
+
+	return recordingsDeleted, framesDeleted, bytesReclaimed, nil
+}
+
+// deleteRecordings deletes each recording's frames (backend blob and DB
+// row) before the recording's own blob and row. The recording row delete
+// would cascade its frame rows in the DB on its own, but deleting the
+// frames first lets this count and size them individually; backend.Delete
+// tolerates an already-missing key, so a sweep interrupted partway
+// through is safe to rerun.
+func (s *Service) deleteRecordings(recordings []Recording) (int, int, int64, error) {
+	var framesDeleted int
+	var bytesReclaimed int64
+
+	for _, recording := range recordings {
+		frames, err := s.frameRepo.GetFramesByRecording(recording.ID)
+		if err != nil {
+			return 0, framesDeleted, bytesReclaimed, err
+		}
+
+		f, b, err := s.deleteFrames(frames)
+		if err != nil {
+			return 0, framesDeleted, bytesReclaimed, err
+		}
+		framesDeleted += f
+		bytesReclaimed += b
+
+		if err := s.backend.Delete(recording.FilePath); err != nil {
+			return 0, framesDeleted, bytesReclaimed, fmt.Errorf("failed to delete recording blob %q: %w", recording.FilePath, err)
+		}
+		if err := s.recordingRepo.DeleteRecording(recording.ID); err != nil {
+			return 0, framesDeleted, bytesReclaimed, fmt.Errorf("failed to delete recording %d: %w", recording.ID, err)
+		}
+		bytesReclaimed += recording.FileSize
+	}
+
+	return len(recordings), framesDeleted, bytesReclaimed, nil
+}
+
+// deleteFrames deletes each frame's backend blob and row.
+func (s *Service) deleteFrames(frames []Frame) (int, int64, error) {
+	var bytesReclaimed int64
+
+	for _, frame := range frames {
+		if err := s.backend.Delete(frame.FilePath); err != nil {
+			return 0, bytesReclaimed, fmt.Errorf("failed to delete frame blob %q: %w", frame.FilePath, err)
+		}
+		if err := s.frameRepo.DeleteFrame(frame.ID); err != nil {
+			return 0, bytesReclaimed, fmt.Errorf("failed to delete frame %d: %w", frame.ID, err)
+		}
+		bytesReclaimed += int64(frame.FileSize)
+	}
+
+	return len(frames), bytesReclaimed, nil
+}
@@ -1,30 +1,66 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"go.uber.org/zap"
+
+	"camera-detection-project/internal/auth"
 	"camera-detection-project/internal/config"
+	"camera-detection-project/internal/eventbus"
+	"camera-detection-project/internal/geo"
+	"camera-detection-project/internal/logging"
+	"camera-detection-project/internal/storage/backend"
 )
 
 // Service provides high-level storage operations
 type Service struct {
-	db              *Database
-	cameraRepo      *CameraRepository
-	recordingRepo   *RecordingRepository
-	frameRepo       *FrameRepository
-	eventRepo       *EventRepository
-	config          *config.Config
-	defaultCameraID int // ID of the main camera
+	db            *Database
+	cameraRepo    *CameraRepository
+	recordingRepo *RecordingRepository
+	frameRepo     *FrameRepository
+	detectionRepo *DetectionRepository
+	eventRepo     *EventRepository
+	userRepo      *UserRepository
+	aclRepo       *CameraACLRepository
+	statsRepo     *SystemStatsRepository
+	broadcastRepo *BroadcastRepository
+	config        *config.Config
+	eventBroker   *eventbus.Broker
+	tokenIssuer   *auth.Issuer
+	frameBatcher  *frameBatcher
+	frameHasher   *frameHasher
+
+	// backend is where finished frame/recording blobs actually get
+	// written; see SaveFrame and FinishRecording. Selected by
+	// cfg.StorageBackend.
+	backend backend.Backend
+
+	// retentionCancel stops the background retention loop started by
+	// NewService; Close calls it.
+	retentionCancel context.CancelFunc
+
+	// Logger is this service's structured logger, built from
+	// cfg.LogLevel/cfg.LogFormat. Every log line it emits carries fields
+	// such as camera_id, recording_id, frame_id, event_type, and severity
+	// so a single frame or event can be traced across capture, detection,
+	// and storage in Loki/ELK.
+	Logger *zap.Logger
 }
 
 // NewService creates a new storage service
 func NewService(cfg *config.Config) (*Service, error) {
 	// Create database configuration
 	dbConfig := &DatabaseConfig{
+		URL:          cfg.DatabaseURL,
 		Host:         cfg.DatabaseHost,
 		Port:         cfg.DatabasePort,
 		User:         cfg.DatabaseUser,
@@ -46,64 +82,165 @@ func NewService(cfg *config.Config) (*Service, error) {
 	cameraRepo := NewCameraRepository(db)
 	recordingRepo := NewRecordingRepository(db)
 	frameRepo := NewFrameRepository(db)
+	detectionRepo := NewDetectionRepository(db)
 	eventRepo := NewEventRepository(db)
+	userRepo := NewUserRepository(db)
+	aclRepo := NewCameraACLRepository(db)
+	statsRepo := NewSystemStatsRepository(db)
+	broadcastRepo := NewBroadcastRepository(db)
+
+	logger, err := logging.New(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+
+	storageBackend, err := backend.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage backend: %w", err)
+	}
 
 	service := &Service{
 		db:            db,
 		cameraRepo:    cameraRepo,
 		recordingRepo: recordingRepo,
 		frameRepo:     frameRepo,
+		detectionRepo: detectionRepo,
 		eventRepo:     eventRepo,
+		userRepo:      userRepo,
+		aclRepo:       aclRepo,
+		statsRepo:     statsRepo,
+		broadcastRepo: broadcastRepo,
 		config:        cfg,
+		tokenIssuer:   auth.NewIssuer(cfg.AuthSecret),
+		Logger:        logger,
+		backend:       storageBackend,
 	}
-
-	// Initialize default camera if needed
-	if err := service.initializeDefaultCamera(); err != nil {
-		return nil, fmt.Errorf("failed to initialize camera: %w", err)
+	service.frameBatcher = newFrameBatcher(frameRepo, logger)
+	service.frameHasher = newFrameHasher()
+
+	// Upsert every camera in cfg.Cameras (or the single implicit camera
+	// described by the top-level RTSPURL/Username/Password fields) into
+	// the cameras table
+	if err := service.syncCameras(); err != nil {
+		return nil, fmt.Errorf("failed to sync cameras: %w", err)
 	}
 
-	log.Println("Storage service initialized successfully")
+	retentionCtx, cancel := context.WithCancel(context.Background())
+	service.retentionCancel = cancel
+	go service.runRetentionLoop(retentionCtx)
+
+	service.Logger.Info("storage service initialized successfully")
 	return service, nil
 }
 
 // Close closes the storage service
 func (s *Service) Close() error {
+	if s.retentionCancel != nil {
+		s.retentionCancel()
+	}
+	if closer, ok := s.backend.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			s.Logger.Warn("failed to close storage backend", zap.Error(err))
+		}
+	}
 	return s.db.Close()
 }
 
-// InitializeDatabase creates tables if they don't exist
-func (s *Service) InitializeDatabase() error {
-	return s.db.CreateTables()
+// SetEventBroker wires an eventbus.Broker into the service so that every
+// event created from this point on is also fanned out to live subscribers
+// (e.g. the SSE stream handler). It is optional: callers that never set one
+// simply don't get live push.
+func (s *Service) SetEventBroker(broker *eventbus.Broker) {
+	s.eventBroker = broker
 }
 
-// initializeDefaultCamera creates or updates the default camera
-func (s *Service) initializeDefaultCamera() error {
-	// Try to get existing camera
-	cameras, err := s.cameraRepo.GetAllCameras()
+// publishEvent fans a just-created event out to the broker, if one is
+// configured. Failures to marshal are logged and otherwise ignored since the
+// event has already been durably persisted.
+func (s *Service) publishEvent(event *Event) {
+	if s.eventBroker == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
 	if err != nil {
-		return err
+		s.Logger.Warn("could not marshal event for broker",
+			zap.Int("event_id", event.ID), zap.String("event_type", event.EventType), zap.Error(err))
+		return
 	}
 
-	if len(cameras) == 0 {
-		// Create default camera
-		camera := &Camera{
+	s.eventBroker.Publish(eventbus.Event{
+		ID:   event.ID,
+		Type: event.EventType,
+		Data: data,
+	})
+}
+
+// InitializeDatabase applies every pending schema migration.
+func (s *Service) InitializeDatabase() error {
+	return s.db.Migrate(context.Background(), "up")
+}
+
+// Migrate applies or rolls back schema migrations; direction is "up",
+// "down", or "redo". It's the entry point for the server's "migrate"
+// subcommand.
+func (s *Service) Migrate(direction string) error {
+	return s.db.Migrate(context.Background(), direction)
+}
+
+// MigrationStatus reports every embedded migration and whether it has been
+// applied, for the "migrate status" subcommand.
+func (s *Service) MigrationStatus() ([]MigrationStatus, error) {
+	return s.db.MigrationStatus()
+}
+
+// syncCameras upserts every camera in cfg.Cameras into the cameras table,
+// keyed by name. If cfg.Cameras is empty, it falls back to a single camera
+// named "Main Camera" built from the top-level RTSPURL/Username/Password
+// fields, exactly as this service behaved before per-camera config
+// existed. A camera whose connection details changed since the last sync
+// (e.g. an edited config.yaml) is updated in place rather than duplicated;
+// renaming a camera is what makes syncCameras treat it as a new one.
+func (s *Service) syncCameras() error {
+	entries := s.config.Cameras
+	if len(entries) == 0 {
+		entries = []config.CameraConfig{{
 			Name:     "Main Camera",
 			RTSPURL:  s.config.RTSPURL,
 			Username: s.config.Username,
 			Password: s.config.Password,
-			Status:   CameraStatusActive,
+		}}
+	}
+
+	for _, entry := range entries {
+		existing, err := s.cameraRepo.GetCameraByName(entry.Name)
+		if err == sql.ErrNoRows {
+			camera := &Camera{
+				Name:     entry.Name,
+				RTSPURL:  entry.RTSPURL,
+				Username: entry.Username,
+				Password: entry.Password,
+				Status:   CameraStatusActive,
+			}
+			if err := s.cameraRepo.CreateCamera(camera); err != nil {
+				return fmt.Errorf("failed to create camera %q: %w", entry.Name, err)
+			}
+			s.Logger.Info("created camera", zap.String("name", entry.Name), zap.Int("camera_id", camera.ID))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up camera %q: %w", entry.Name, err)
 		}
 
-		if err := s.cameraRepo.CreateCamera(camera); err != nil {
-			return fmt.Errorf("failed to create default camera: %w", err)
+		if existing.RTSPURL == entry.RTSPURL && existing.Username == entry.Username && existing.Password == entry.Password {
+			s.Logger.Info("using existing camera", zap.String("name", entry.Name), zap.Int("camera_id", existing.ID))
+			continue
 		}
 
-		s.defaultCameraID = camera.ID
-		log.Printf("Created default camera with ID: %d", camera.ID)
-	} else {
-		// Use first camera as default
-		s.defaultCameraID = cameras[0].ID
-		log.Printf("Using existing camera with ID: %d", s.defaultCameraID)
+		if err := s.cameraRepo.UpdateCameraConnection(existing.ID, entry.RTSPURL, entry.Username, entry.Password); err != nil {
+			return fmt.Errorf("failed to update camera %q: %w", entry.Name, err)
+		}
+		s.Logger.Info("updated camera connection", zap.String("name", entry.Name), zap.Int("camera_id", existing.ID))
 	}
 
 	return nil
@@ -111,10 +248,11 @@ func (s *Service) initializeDefaultCamera() error {
 
 // Recording methods
 
-// StartRecording creates a new recording record
-func (s *Service) StartRecording(filePath string) (*Recording, error) {
+// StartRecording creates a new recording record for cameraID
+func (s *Service) StartRecording(cameraID int, filePath string) (*Recording, error) {
 	recording := &Recording{
-		CameraID:  s.defaultCameraID,
+		CameraID:  cameraID,
+		ClipType:  ClipTypeContinuous,
 		FilePath:  filePath,
 		StartTime: time.Now(),
 		Status:    RecordingStatusRecording,
@@ -126,24 +264,101 @@ func (s *Service) StartRecording(filePath string) (*Recording, error) {
 		return nil, fmt.Errorf("failed to create recording: %w", err)
 	}
 
-	log.Printf("Started recording: %s (ID: %d)", filePath, recording.ID)
+	s.Logger.Info("started recording",
+		zap.Int("recording_id", recording.ID), zap.Int("camera_id", recording.CameraID), zap.String("file_path", filePath))
+	return recording, nil
+}
+
+// recordingKey builds the backend key a recording or event clip is written
+// under, namespaced by camera so GetStorageUsage/List can be scoped later.
+func recordingKey(cameraID int, filePath string) string {
+	return fmt.Sprintf("recordings/cam_%d/%s", cameraID, filepath.Base(filePath))
+}
+
+// writeToBackend copies the local file at localPath into s.backend under
+// key and removes the local copy afterward, since the bytes are now
+// durably stored there instead.
+func (s *Service) writeToBackend(key, localPath string) (int64, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %q: %w", localPath, err)
+	}
+	defer f.Close()
+
+	size, err := s.backend.Put(context.Background(), key, f)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Remove(localPath); err != nil {
+		s.Logger.Warn("could not remove local file after writing to storage backend",
+			zap.String("local_path", localPath), zap.String("key", key), zap.Error(err))
+	}
+
+	return size, nil
+}
+
+// CreateEventRecording records an already-written pre-event clip (see
+// packets.Timeline/recorder.WriteClip), rather than the StartRecording/
+// FinishRecording two-phase flow used for an ongoing continuous recording:
+// by the time this is called the clip file is already complete, so it's
+// written straight through to the storage backend.
+func (s *Service) CreateEventRecording(cameraID, eventID int, filePath string, fileSize int64, duration int) (*Recording, error) {
+	now := time.Now()
+
+	key := recordingKey(cameraID, filePath)
+	size, err := s.writeToBackend(key, filePath)
+	if err != nil {
+		s.Logger.Warn("could not write event clip to storage backend",
+			zap.Int("camera_id", cameraID), zap.Int("event_id", eventID), zap.String("file_path", filePath), zap.Error(err))
+		key = filePath
+		size = fileSize
+	}
+
+	recording := &Recording{
+		CameraID:  cameraID,
+		EventID:   &eventID,
+		ClipType:  ClipTypeEvent,
+		FilePath:  key,
+		FileSize:  size,
+		Duration:  duration,
+		StartTime: now.Add(-time.Duration(duration) * time.Second),
+		EndTime:   &now,
+		Status:    RecordingStatusCompleted,
+		Quality:   "1080p", // TODO: detect from stream
+		Codec:     "h264",
+	}
+
+	if err := s.recordingRepo.CreateRecording(recording); err != nil {
+		return nil, fmt.Errorf("failed to create event recording: %w", err)
+	}
+
+	s.Logger.Info("saved event clip",
+		zap.Int("recording_id", recording.ID), zap.Int("camera_id", cameraID),
+		zap.Int("event_id", eventID), zap.String("key", recording.FilePath))
 	return recording, nil
 }
 
-// FinishRecording updates recording with final information
+// FinishRecording updates recording with final information. The finished
+// file is written through to the storage backend and recording.FilePath is
+// replaced with its backend key, so the same recordings table row works
+// unchanged whether OutputDir is the final home for the clip or just a
+// local staging area in front of S3/WebDAV/SSH.
 func (s *Service) FinishRecording(recordingID int, filePath string) error {
 	recording, err := s.recordingRepo.GetRecording(recordingID)
 	if err != nil {
 		return fmt.Errorf("failed to get recording: %w", err)
 	}
 
-	// Get file information
-	fileInfo, err := os.Stat(filePath)
+	key := recordingKey(recording.CameraID, filePath)
+	size, err := s.writeToBackend(key, filePath)
 	if err != nil {
-		log.Printf("Warning: could not get file info for %s: %v", filePath, err)
+		s.Logger.Warn("could not write recording to storage backend",
+			zap.Int("recording_id", recordingID), zap.String("file_path", filePath), zap.Error(err))
 		recording.FileSize = 0
 	} else {
-		recording.FileSize = fileInfo.Size()
+		recording.FilePath = key
+		recording.FileSize = size
 	}
 
 	// Calculate duration
@@ -156,52 +371,165 @@ func (s *Service) FinishRecording(recordingID int, filePath string) error {
 		return fmt.Errorf("failed to update recording: %w", err)
 	}
 
-	log.Printf("Finished recording: %s (Duration: %ds, Size: %d bytes)",
-		filePath, recording.Duration, recording.FileSize)
+	s.Logger.Info("finished recording",
+		zap.Int("recording_id", recordingID), zap.String("key", recording.FilePath),
+		zap.Int("duration_seconds", recording.Duration), zap.Int64("file_size_bytes", recording.FileSize))
 
 	return nil
 }
 
 // Frame methods
 
-// SaveFrame creates a new frame record
-func (s *Service) SaveFrame(filePath string, recordingID *int) (*Frame, error) {
-	// Get file information
-	fileInfo, err := os.Stat(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %w", err)
-	}
-
+// ErrDuplicateFrame is returned by SaveFrame when an incoming frame's pHash
+// is within cfg.FrameDedupHamming bits of the last frame stored for the
+// same camera, so the caller can skip it without treating it as a failure.
+var ErrDuplicateFrame = errors.New("storage: frame rejected as a near-duplicate of the last stored frame")
+
+// SaveFrame creates a new frame record. The file at filePath is written
+// through to the storage backend first (see writeToBackend), and the
+// resulting key is what gets stored in the frames table. The DB insert
+// itself is folded into the next frameBatcher flush (a single multi-row
+// INSERT, fired every ~100ms or once 100 frames are pending) rather than
+// issued as its own round trip, so the capture hot path isn't blocked
+// per-frame on DB latency on top of backend latency.
+//
+// Before any of that, filePath's pHash is compared against cameraID's last
+// stored frame; if they're within cfg.FrameDedupHamming bits of each
+// other, the frame is rejected with ErrDuplicateFrame instead of being
+// written and inserted, to avoid filling the archive with near-identical
+// frames of a static scene. Pass fromDetection true to skip this check for
+// a frame known to matter regardless of similarity (e.g. one captured
+// because a detection already fired on it).
+func (s *Service) SaveFrame(cameraID int, filePath string, recordingID *int, fromDetection bool) (*Frame, error) {
 	frame := &Frame{
 		RecordingID: recordingID,
-		CameraID:    s.defaultCameraID,
-		FilePath:    filePath,
-		FileSize:    int(fileInfo.Size()),
+		CameraID:    cameraID,
 		Timestamp:   time.Now(),
 		Width:       1920, // TODO: detect from image
 		Height:      1080, // TODO: detect from image
 	}
 
-	if err := s.frameRepo.CreateFrame(frame); err != nil {
+	if hash, err := s.frameHasher.Hash(filePath); err != nil {
+		s.Logger.Warn("failed to compute frame phash, skipping dedup check",
+			zap.Int("camera_id", cameraID), zap.Error(err))
+	} else {
+		frame.PHash = &hash
+		if !fromDetection {
+			dup, err := s.isDuplicateFrame(cameraID, hash)
+			if err != nil {
+				s.Logger.Warn("failed to check frame for duplicates",
+					zap.Int("camera_id", cameraID), zap.Error(err))
+			} else if dup {
+				return nil, ErrDuplicateFrame
+			}
+		}
+	}
+
+	frame.CellID, frame.TakenAtLocal = s.locateFrame(cameraID, frame.Timestamp)
+
+	key := fmt.Sprintf("frames/cam_%d/%s", frame.CameraID, filepath.Base(filePath))
+	size, err := s.writeToBackend(key, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write frame to storage backend: %w", err)
+	}
+	frame.FilePath = key
+	frame.FileSize = int(size)
+
+	if err := s.frameBatcher.CreateFrame(frame); err != nil {
 		return nil, fmt.Errorf("failed to create frame: %w", err)
 	}
 
-	log.Printf("Saved frame: %s (ID: %d)", filePath, frame.ID)
+	s.Logger.Info("saved frame",
+		zap.Int("frame_id", frame.ID), zap.Int("camera_id", frame.CameraID), zap.String("key", key))
 	return frame, nil
 }
 
-// UpdateFrameProcessed marks frame as processed with detection results
-func (s *Service) UpdateFrameProcessed(frameID int, hasDetection bool, thumbnailPath *string) error {
+// isDuplicateFrame reports whether hash is within cfg.FrameDedupHamming
+// bits of cameraID's most recently stored frame.
+func (s *Service) isDuplicateFrame(cameraID int, hash int64) (bool, error) {
+	last, err := s.frameRepo.GetLastPHash(cameraID)
+	if err != nil {
+		return false, err
+	}
+	if last == nil {
+		return false, nil
+	}
+	return hammingDistance(hash, *last) <= s.config.FrameDedupHamming, nil
+}
+
+// frameCellPrecision is the geohash length stored on each frame/detection.
+// GetFrameClusters truncates it further at query time for coarser tiles.
+const frameCellPrecision = 9
+
+// locateFrame derives the geo cell and camera-local capture time for a
+// frame, based on cameraID's location. Cameras without a known location
+// get an empty cell and TakenAtLocal falling back to UTC.
+func (s *Service) locateFrame(cameraID int, capturedAt time.Time) (cellID string, takenAtLocal time.Time) {
+	camera, err := s.cameraRepo.GetCamera(cameraID)
+	if err != nil {
+		return "", capturedAt.UTC()
+	}
+
+	loc := time.UTC
+	if camera.Timezone != "" {
+		if tz, err := time.LoadLocation(camera.Timezone); err == nil {
+			loc = tz
+		}
+	}
+	takenAtLocal = capturedAt.In(loc)
+
+	if !camera.HasLocation() {
+		return "", takenAtLocal
+	}
+
+	return geo.Encode(*camera.Latitude, *camera.Longitude, frameCellPrecision), takenAtLocal
+}
+
+// CreateDetection records an object detection result for a frame, inheriting
+// the frame's geo cell so GetDetectionsByCell can filter without a join.
+func (s *Service) CreateDetection(frameID int, objectType string, confidence float64, boundingBox string) error {
 	frame, err := s.frameRepo.GetFrame(frameID)
 	if err != nil {
 		return fmt.Errorf("failed to get frame: %w", err)
 	}
 
-	frame.HasDetection = hasDetection
-	frame.Processed = true
-	frame.ThumbnailPath = thumbnailPath
+	detection := &Detection{
+		FrameID:     frameID,
+		ObjectType:  objectType,
+		Confidence:  confidence,
+		BoundingBox: boundingBox,
+		CellID:      frame.CellID,
+		Timestamp:   frame.Timestamp,
+	}
+
+	if err := s.detectionRepo.CreateDetection(detection); err != nil {
+		return fmt.Errorf("failed to create detection: %w", err)
+	}
+
+	return nil
+}
 
-	if err := s.frameRepo.UpdateFrame(frame); err != nil {
+// GetFrameClusters groups frames captured between start and end by geo cell,
+// for map heat-tiles and per-camera activity timelines.
+func (s *Service) GetFrameClusters(cellPrefixLen int, start, end time.Time) ([]Cluster, error) {
+	return s.frameRepo.GetFrameClusters(cellPrefixLen, start, end)
+}
+
+// GetDetectionsByCell retrieves detections within a geo cell and time range.
+func (s *Service) GetDetectionsByCell(cellID string, start, end time.Time) ([]Detection, error) {
+	return s.detectionRepo.GetDetectionsByCell(cellID, start, end)
+}
+
+// UpdateFrameProcessed marks frame as processed with detection results. Like
+// SaveFrame, the write is folded into the next frameBatcher flush instead of
+// running immediately.
+func (s *Service) UpdateFrameProcessed(frameID int, hasDetection bool, thumbnailPath *string) error {
+	if err := s.frameBatcher.UpdateFrameProcessed(FrameProcessedUpdate{
+		FrameID:       frameID,
+		HasDetection:  hasDetection,
+		Processed:     true,
+		ThumbnailPath: thumbnailPath,
+	}); err != nil {
 		return fmt.Errorf("failed to update frame: %w", err)
 	}
 
@@ -213,28 +541,22 @@ func (s *Service) GetUnprocessedFrames(limit int) ([]Frame, error) {
 	return s.frameRepo.GetUnprocessedFrames(limit)
 }
 
-// Event methods
-
-// CreateEvent creates a new event
-func (s *Service) CreateEvent(eventType, severity, title, message string, metadata *string) error {
-	event := &Event{
-		CameraID:  &s.defaultCameraID,
-		EventType: eventType,
-		Severity:  severity,
-		Title:     title,
-		Message:   message,
-		Metadata:  metadata,
-		Timestamp: time.Now(),
-	}
-
-	if err := s.eventRepo.CreateEvent(event); err != nil {
-		return fmt.Errorf("failed to create event: %w", err)
-	}
+// ListAllCameras retrieves every camera, unscoped by ACL. Intended for
+// internal backend jobs (e.g. the metrics sampler) rather than
+// user-facing requests, which should go through GetCamerasForUser instead.
+func (s *Service) ListAllCameras() ([]Camera, error) {
+	return s.cameraRepo.GetAllCameras()
+}
 
-	log.Printf("Created event: %s (%s) - %s", eventType, severity, title)
-	return nil
+// GetFramesByTimeRange retrieves a camera's frames in a time range,
+// unscoped by ACL. Intended for internal backend jobs; user-facing
+// requests should go through GetFramesByTimeRangeForUser instead.
+func (s *Service) GetFramesByTimeRange(cameraID int, start, end time.Time, limit int) ([]Frame, error) {
+	return s.frameRepo.GetFramesByTimeRange(cameraID, start, end, limit)
 }
 
+// Event methods
+
 // CreateSystemEvent creates a system-level event (no camera association)
 func (s *Service) CreateSystemEvent(eventType, severity, title, message string) error {
 	event := &Event{
@@ -249,7 +571,9 @@ func (s *Service) CreateSystemEvent(eventType, severity, title, message string)
 		return fmt.Errorf("failed to create system event: %w", err)
 	}
 
-	log.Printf("Created system event: %s (%s) - %s", eventType, severity, title)
+	s.Logger.Info("created system event",
+		zap.String("event_type", eventType), zap.String("severity", severity), zap.String("title", title))
+	s.publishEvent(event)
 	return nil
 }
 
@@ -258,6 +582,12 @@ func (s *Service) GetRecentEvents(limit int) ([]Event, error) {
 	return s.eventRepo.GetRecentEvents(limit)
 }
 
+// GetEventsAfter retrieves events created after afterID, used by the SSE
+// stream handler to replay events a reconnecting client missed.
+func (s *Service) GetEventsAfter(afterID int, limit int) ([]Event, error) {
+	return s.eventRepo.GetEventsAfter(afterID, limit)
+}
+
 // Statistics methods
 
 // GetDatabaseStats returns database statistics
@@ -265,38 +595,182 @@ func (s *Service) GetDatabaseStats() (map[string]int, error) {
 	return s.db.GetDatabaseStats()
 }
 
-// GetCameraStatus returns current camera status
-func (s *Service) GetCameraStatus() (*Camera, error) {
-	return s.cameraRepo.GetCamera(s.defaultCameraID)
+// GetCameraStatus returns cameraID's current status.
+func (s *Service) GetCameraStatus(cameraID int) (*Camera, error) {
+	return s.cameraRepo.GetCamera(cameraID)
 }
 
-// UpdateCameraStatus updates camera status
-func (s *Service) UpdateCameraStatus(status string) error {
-	return s.cameraRepo.UpdateCameraStatus(s.defaultCameraID, status)
+// UpdateCameraStatus updates a specific camera's status
+func (s *Service) UpdateCameraStatus(cameraID int, status string) error {
+	return s.cameraRepo.UpdateCameraStatus(cameraID, status)
 }
 
-// Cleanup methods
+// GetCamera retrieves a single camera by ID, unscoped by ACL. Intended for
+// internal backend jobs (e.g. camera.Manager); user-facing requests should
+// go through GetCamerasForUser instead.
+func (s *Service) GetCamera(id int) (*Camera, error) {
+	return s.cameraRepo.GetCamera(id)
+}
 
-// CleanupOldRecordings removes old recording records and files
-func (s *Service) CleanupOldRecordings(olderThanDays int) error {
-	// This would implement cleanup logic
-	log.Printf("TODO: Cleanup recordings older than %d days", olderThanDays)
-	return nil
+// CreateCamera persists a new camera row.
+func (s *Service) CreateCamera(camera *Camera) error {
+	return s.cameraRepo.CreateCamera(camera)
 }
 
 // GetStorageUsage calculates total storage usage
 func (s *Service) GetStorageUsage() (int64, error) {
-	var totalSize int64
+	return s.backend.Usage()
+}
 
-	err := filepath.Walk(s.config.OutputDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip files we can't access
-		}
-		if !info.IsDir() {
-			totalSize += info.Size()
+// Auth / ACL methods
+
+// RegisterUser creates a new user account with a bcrypt-hashed password.
+func (s *Service) RegisterUser(username, password string) (*User, error) {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{Username: username, PasswordHash: hash}
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// AuthenticateUser checks a username/password pair and, on success, issues a
+// session token for it.
+func (s *Service) AuthenticateUser(username, password string) (*User, string, error) {
+	user, err := s.userRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid username or password")
+	}
+
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		return nil, "", fmt.Errorf("invalid username or password")
+	}
+
+	token := s.tokenIssuer.Issue(user.ID)
+	return user, token, nil
+}
+
+// VerifyToken validates a session token and returns the user ID it asserts.
+func (s *Service) VerifyToken(token string) (int, error) {
+	return s.tokenIssuer.Verify(token)
+}
+
+// GrantCameraAccess gives a user a role on a camera, creating or updating
+// their camera_acl row.
+func (s *Service) GrantCameraAccess(userID, cameraID int, role string) error {
+	return s.aclRepo.GrantAccess(userID, cameraID, role)
+}
+
+// GetUserRole returns the role a user has on a camera.
+func (s *Service) GetUserRole(userID, cameraID int) (string, error) {
+	return s.aclRepo.GetRole(userID, cameraID)
+}
+
+// GetCamerasForUser returns every camera the user has any role on.
+func (s *Service) GetCamerasForUser(userID int) ([]Camera, error) {
+	ids, err := s.aclRepo.GetCameraIDsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+
+	cameras, err := s.cameraRepo.GetAllCameras()
+	if err != nil {
+		return nil, err
+	}
+
+	var scoped []Camera
+	for _, camera := range cameras {
+		if allowed[camera.ID] {
+			scoped = append(scoped, camera)
 		}
-		return nil
-	})
+	}
+
+	return scoped, nil
+}
+
+// GetRecordingsByCameraForUser returns a camera's recordings if the user has
+// any role on it, and an error otherwise.
+func (s *Service) GetRecordingsByCameraForUser(userID, cameraID, limit int) ([]Recording, error) {
+	if _, err := s.aclRepo.GetRole(userID, cameraID); err != nil {
+		return nil, err
+	}
+	return s.recordingRepo.GetRecordingsByCamera(cameraID, limit)
+}
+
+// GetFramesByTimeRangeForUser returns a camera's frames in a time range if
+// the user has any role on it, and an error otherwise.
+func (s *Service) GetFramesByTimeRangeForUser(userID, cameraID int, start, end time.Time, limit int) ([]Frame, error) {
+	if _, err := s.aclRepo.GetRole(userID, cameraID); err != nil {
+		return nil, err
+	}
+	return s.frameRepo.GetFramesByTimeRange(cameraID, start, end, limit)
+}
+
+// UpsertSystemStats writes the daily aggregate for date, for the metrics
+// sampler's nightly flush.
+func (s *Service) UpsertSystemStats(date time.Time, totalRecordings, totalFrames, totalDetections int, storageUsedBytes int64, uptimeSeconds int) error {
+	return s.statsRepo.Upsert(date, totalRecordings, totalFrames, totalDetections, storageUsedBytes, uptimeSeconds)
+}
 
-	return totalSize, err
-}
\ No newline at end of file
+// Broadcast methods
+
+// CreateEventForCamera creates an event attributed to a specific camera,
+// for callers (e.g. a per-camera broadcast pipeline) that aren't acting on
+// behalf of the default camera.
+func (s *Service) CreateEventForCamera(cameraID int, eventType, severity, title, message string) error {
+	event := &Event{
+		CameraID:  &cameraID,
+		EventType: eventType,
+		Severity:  severity,
+		Title:     title,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+
+	if err := s.eventRepo.CreateEvent(event); err != nil {
+		return fmt.Errorf("failed to create camera event: %w", err)
+	}
+
+	s.Logger.Info("created event for camera",
+		zap.Int("camera_id", cameraID), zap.String("event_type", eventType),
+		zap.String("severity", severity), zap.String("title", title))
+	s.publishEvent(event)
+	return nil
+}
+
+// CreateBroadcast records the start of a new egress pipeline for a camera.
+func (s *Service) CreateBroadcast(cameraID int, protocol, targetURL string) (*Broadcast, error) {
+	broadcast := &Broadcast{
+		CameraID:  cameraID,
+		Protocol:  protocol,
+		TargetURL: targetURL,
+		Status:    BroadcastStatusActive,
+		StartedAt: time.Now(),
+	}
+
+	if err := s.broadcastRepo.CreateBroadcast(broadcast); err != nil {
+		return nil, fmt.Errorf("failed to create broadcast: %w", err)
+	}
+	return broadcast, nil
+}
+
+// FinishBroadcast marks a broadcast as stopped (or errored) and records its
+// final byte count.
+func (s *Service) FinishBroadcast(id int, status string, bytesSent int64) error {
+	return s.broadcastRepo.UpdateBroadcastStatus(id, status, bytesSent)
+}
+
+// GetBroadcastsByCamera retrieves the most recent broadcasts for a camera.
+func (s *Service) GetBroadcastsByCamera(cameraID int, limit int) ([]Broadcast, error) {
+	return s.broadcastRepo.GetBroadcastsByCamera(cameraID, limit)
+}